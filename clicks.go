@@ -0,0 +1,78 @@
+/*
+   Copyright 2012 the go.wde authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package wde
+
+import (
+	"image"
+	"time"
+)
+
+/*
+DoubleClickInterval and DoubleClickDistance are how close in time, and in
+pixels along each axis, successive presses of a button must be to make a
+multiple click, on window systems that have no setting of their own.
+*/
+var (
+	DoubleClickInterval = 500 * time.Millisecond
+	DoubleClickDistance = 4
+)
+
+/*
+A ClickCounter works out the Clicks of mouse button events for backends
+whose window system does not count them. Interval and Distance override
+DoubleClickInterval and DoubleClickDistance when they are not zero.
+*/
+type ClickCounter struct {
+	Interval time.Duration
+	Distance int
+
+	which  Button
+	when   time.Duration
+	where  image.Point
+	clicks int
+}
+
+// Press returns the click count of a press of which at where, at time when.
+func (c *ClickCounter) Press(which Button, where image.Point, when time.Duration) int {
+	interval, distance := c.Interval, c.Distance
+	if interval == 0 {
+		interval = DoubleClickInterval
+	}
+	if distance == 0 {
+		distance = DoubleClickDistance
+	}
+	d := where.Sub(c.where)
+	if c.clicks != 0 && which == c.which &&
+		when >= c.when && when-c.when <= interval &&
+		d.X <= distance && -d.X <= distance &&
+		d.Y <= distance && -d.Y <= distance {
+		c.clicks++
+	} else {
+		c.clicks = 1
+	}
+	c.which, c.when, c.where = which, when, where
+	return c.clicks
+}
+
+// Release returns the click count of a release of which, which is that of
+// the press it ends.
+func (c *ClickCounter) Release(which Button) int {
+	if c.clicks == 0 || which != c.which {
+		return 1
+	}
+	return c.clicks
+}
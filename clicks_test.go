@@ -0,0 +1,100 @@
+/*
+   Copyright 2012 the go.wde authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package wde
+
+import (
+	"image"
+	"testing"
+	"time"
+)
+
+func TestClickCounter(t *testing.T) {
+	type press struct {
+		which Button
+		where image.Point
+		when  time.Duration
+		want  int
+	}
+	ms := time.Millisecond
+	for _, test := range []struct {
+		name    string
+		presses []press
+	}{
+		{"triple click", []press{
+			{LeftButton, image.Pt(10, 10), 0, 1},
+			{LeftButton, image.Pt(10, 10), 100 * ms, 2},
+			{LeftButton, image.Pt(10, 10), 200 * ms, 3},
+			{LeftButton, image.Pt(10, 10), 300 * ms, 4},
+		}},
+		{"interval boundary", []press{
+			{LeftButton, image.Pt(10, 10), 0, 1},
+			{LeftButton, image.Pt(10, 10), 500 * ms, 2},
+			{LeftButton, image.Pt(10, 10), 1000*ms + 1, 1},
+		}},
+		{"clock going backwards", []press{
+			{LeftButton, image.Pt(10, 10), 500 * ms, 1},
+			{LeftButton, image.Pt(10, 10), 400 * ms, 1},
+		}},
+		{"distance threshold", []press{
+			{LeftButton, image.Pt(10, 10), 0, 1},
+			{LeftButton, image.Pt(14, 6), 100 * ms, 2},
+			// measured from the press before, not the first
+			{LeftButton, image.Pt(10, 6), 200 * ms, 3},
+			{LeftButton, image.Pt(10, 1), 300 * ms, 1},
+			{LeftButton, image.Pt(15, 1), 400 * ms, 1},
+		}},
+		{"other button", []press{
+			{LeftButton, image.Pt(10, 10), 0, 1},
+			{LeftButton, image.Pt(10, 10), 100 * ms, 2},
+			{RightButton, image.Pt(10, 10), 200 * ms, 1},
+			{LeftButton, image.Pt(10, 10), 300 * ms, 1},
+			{LeftButton, image.Pt(10, 10), 400 * ms, 2},
+		}},
+	} {
+		var c ClickCounter
+		c.Interval = 500 * ms
+		c.Distance = 4
+		for i, p := range test.presses {
+			if got := c.Press(p.which, p.where, p.when); got != p.want {
+				t.Errorf("%s: press %d counted %d clicks, want %d", test.name, i, got, p.want)
+			}
+			if got := c.Release(p.which); got != p.want {
+				t.Errorf("%s: release %d counted %d clicks, want %d", test.name, i, got, p.want)
+			}
+		}
+	}
+}
+
+// A counter with no Interval or Distance of its own uses the package's.
+func TestClickCounterDefaults(t *testing.T) {
+	defer func(i time.Duration, d int) {
+		DoubleClickInterval, DoubleClickDistance = i, d
+	}(DoubleClickInterval, DoubleClickDistance)
+	DoubleClickInterval, DoubleClickDistance = time.Second, 1
+
+	var c ClickCounter
+	c.Press(LeftButton, image.Pt(0, 0), 0)
+	if got := c.Press(LeftButton, image.Pt(1, 1), 900*time.Millisecond); got != 2 {
+		t.Errorf("second press counted %d clicks, want 2", got)
+	}
+	if got := c.Press(LeftButton, image.Pt(3, 1), 1800*time.Millisecond); got != 1 {
+		t.Errorf("distant press counted %d clicks, want 1", got)
+	}
+	if got := c.Release(RightButton); got != 1 {
+		t.Errorf("release of an unpressed button counted %d clicks, want 1", got)
+	}
+}
@@ -14,10 +14,34 @@
    limitations under the License.
 */
 
+/*
+Package cocoa is the go.wde backend for OS X, drawing through the gomacdraw
+framework, which must be installed first; see the README.
+
+The framework leaves out some of what wde.Window offers, so these gaps
+remain:
+
+  - LockSize does nothing.
+  - FlushImage always redraws the whole window.
+  - Only the left mouse button is reported.
+  - Dead keys and input methods are not seen, so TextCompositionEvents are
+    never sent, and each key types at most one character.
+  - ExposeEvents are never sent. Cocoa keeps the window's contents, and the
+    framework redraws them from the last flushed frame itself.
+  - wde.Screens reports wde.ErrUnsupported.
+  - Of the WindowOptions, only the title, position and state are applied.
+    A window can only be minimized or made fullscreen once it is on the
+    screen, so those states are applied as Show first shows it.
+  - Timestamps are taken when events are read, not when they happened.
+
+Positions, scale, window states, focus and scrolling are handled here, on
+the framework's NSWindow, rather than by the framework.
+*/
 package cocoa
 
-// #cgo darwin LDFLAGS: -framework gomacdraw
+// #cgo darwin LDFLAGS: -framework gomacdraw -framework Cocoa
 // #include "gomacdraw/gmd.h"
+// #include "window_darwin.h"
 // #include "stdlib.h"
 import "C"
 
@@ -40,6 +64,10 @@ func init() {
 		w, err = NewWindow(width, height)
 		return
 	}
+	wde.BackendNewWindowWithOptions = func(opts wde.WindowOptions) (w wde.Window, err error) {
+		w, err = NewWindowWithOptions(opts)
+		return
+	}
 	wde.BackendRun = Run
 	wde.BackendStop = Stop
 	runtime.LockOSThread()
@@ -66,15 +94,45 @@ type Window struct {
 	cw     C.GMDWindow
 	im     Image
 	oplock sync.Mutex
-	ec     chan interface{}
+	ec     chan wde.Event
+
+	// showState is the state Show puts the window in when it first shows
+	// it, and is then forgotten
+	showState wde.WindowState
 }
 
 func NewWindow(width, height int) (w *Window, err error) {
-	cw := C.openWindow()
+	return NewWindowWithOptions(wde.WindowOptions{Width: width, Height: height})
+}
+
+/*
+NewWindowWithOptions creates a window as opts describe. The window stays
+off the screen until Show, and all that is applied is applied before then,
+but for the minimized and fullscreen states.
+*/
+func NewWindowWithOptions(opts wde.WindowOptions) (w *Window, err error) {
+	cw := C.openHiddenWindow()
+	if cw == nil {
+		err = errors.New("cocoa: could not load the gomacdraw window")
+		return
+	}
 	w = &Window{
 		cw: cw,
 	}
-	w.SetSize(width, height)
+	C.watchWindow(cw)
+	w.SetSize(opts.Width, opts.Height)
+	if opts.Title != "" {
+		w.SetTitle(opts.Title)
+	}
+	if opts.Position != nil {
+		w.SetPosition(opts.Position.X, opts.Position.Y)
+	}
+	switch opts.State {
+	case wde.StateMaximized:
+		w.Maximize()
+	case wde.StateMinimized, wde.StateFullscreen:
+		w.showState = opts.State
+	}
 	return
 }
 
@@ -105,15 +163,70 @@ func (w *Window) Size() (width, height int) {
 	return
 }
 
+// Position reports where the top-left corner of the window's frame is, from
+// the top left of the primary screen.
+func (w *Window) Position() (x, y int) {
+	var cx, cy _Ctype_int
+	C.getWindowPosition(w.cw, &cx, &cy)
+	return int(cx), int(cy)
+}
+
+func (w *Window) SetPosition(x, y int) {
+	C.setWindowPosition(w.cw, _Ctype_int(x), _Ctype_int(y))
+}
+
+// Center moves the window to the middle of the visible part of its screen,
+// leaving out the menu bar and the Dock.
+func (w *Window) Center() {
+	C.centerWindow(w.cw)
+}
+
+// Scale reports the window's backing scale factor. The framework draws the
+// screen at one pixel per point, so Cocoa stretches it on Retina displays.
+func (w *Window) Scale() float64 {
+	return float64(C.getWindowScale(w.cw))
+}
+
+// SetFullscreen gives the window a space of its own, as the green button
+// does, and as Cocoa does it the change is animated.
+func (w *Window) SetFullscreen(fullscreen bool) {
+	var f _Ctype_int
+	if fullscreen {
+		f = 1
+	}
+	C.setWindowFullscreen(w.cw, f)
+}
+
+// Maximize zooms the window, which Cocoa sizes to fit its screen.
+func (w *Window) Maximize() {
+	C.maximizeWindow(w.cw)
+}
+
+func (w *Window) Minimize() {
+	C.minimizeWindow(w.cw)
+}
+
+func (w *Window) Restore() {
+	C.restoreWindow(w.cw)
+}
+
 func (w *Window) LockSize(lock bool) {
 
 }
 
 func (w *Window) Show() {
 	w.oplock.Lock()
-	defer w.oplock.Unlock()
-
 	C.showWindow(w.cw)
+	state := w.showState
+	w.showState = wde.StateNormal
+	w.oplock.Unlock()
+
+	switch state {
+	case wde.StateMinimized:
+		w.Minimize()
+	case wde.StateFullscreen:
+		w.SetFullscreen(true)
+	}
 }
 
 func (w *Window) resizeBuffer(width, height int) (im wde.Image) {
@@ -155,6 +268,8 @@ newbuffer:
 	return
 }
 
+// FlushImage always flushes the whole screen; gomacdraw has no way to
+// flush part of it.
 func (w *Window) FlushImage(bounds ...image.Rectangle) {
 	w.oplock.Lock()
 	defer w.oplock.Unlock()
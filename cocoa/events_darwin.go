@@ -17,14 +17,20 @@
 package cocoa
 
 // #include "gomacdraw/gmd.h"
+// #include "window_darwin.h"
 import "C"
 
 import (
 	"fmt"
 	"github.com/skelterjohn/go.wde"
+	"time"
 	// "strings"
 )
 
+// The framework does not pass on NSEvent timestamps, so events are stamped
+// as they are read, relative to when the package was loaded.
+var epoch = time.Now()
+
 func getButton(b int) (which wde.Button) {
 	switch b {
 	case 0:
@@ -33,6 +39,41 @@ func getButton(b int) (which wde.Button) {
 	return
 }
 
+// NSEvent modifier flags, as passed along with key events.
+const (
+	nsAlphaShiftKeyMask = 1 << 16
+	nsShiftKeyMask      = 1 << 17
+	nsControlKeyMask    = 1 << 18
+	nsAlternateKeyMask  = 1 << 19
+	nsCommandKeyMask    = 1 << 20
+)
+
+func modifiersForFlags(flags int) (mods wde.Modifiers) {
+	if flags&nsShiftKeyMask != 0 {
+		mods |= wde.ModShift
+	}
+	if flags&nsControlKeyMask != 0 {
+		mods |= wde.ModControl
+	}
+	if flags&nsAlternateKeyMask != 0 {
+		mods |= wde.ModAlt
+	}
+	if flags&nsCommandKeyMask != 0 {
+		mods |= wde.ModSuper
+	}
+	if flags&nsAlphaShiftKeyMask != 0 {
+		mods |= wde.ModCapsLock
+	}
+	return
+}
+
+// isTextRune reports whether r is a character to be typed, rather than a
+// control character or one of the private use characters AppKit gives the
+// arrow and function keys.
+func isTextRune(r rune) bool {
+	return r >= 0x20 && r != 0x7f && !(r >= 0xf700 && r <= 0xf8ff)
+}
+
 func containsGlyph(haystack []string, needle string) bool {
 	for _, v := range haystack {
 		if needle == v {
@@ -42,52 +83,86 @@ func containsGlyph(haystack []string, needle string) bool {
 	return false
 }
 
-func (w *Window) EventChan() (events <-chan interface{}) {
+func (w *Window) EventChan() (events <-chan wde.Event) {
 	downKeys := make(map[string]bool)
-	ec := make(chan interface{})
-	go func(ec chan<- interface{}) {
+	// The framework only passes modifier flags with key events, so mouse
+	// events get the flags from the most recent one, which includes
+	// presses and releases of the modifier keys themselves.
+	var mods wde.Modifiers
+	var clicks wde.ClickCounter
+	// the window's state and scale as last reported, since their
+	// notifications also come when neither changed
+	state := wde.StateNormal
+	scale := w.Scale()
+	ec := make(chan wde.Event)
+	go func(ec chan<- wde.Event) {
 	eventloop:
 		for {
 			e := C.getNextEvent(w.cw)
+			when := time.Since(epoch)
 			switch e.kind {
 			case C.GMDNoop:
 				continue
 			case C.GMDMouseDown:
 				var mde wde.MouseDownEvent
+				mde.Source = w
+				mde.When = when
+				mde.Mods = mods
 				mde.Where.X = int(e.data[0])
 				mde.Where.Y = int(e.data[1])
 				mde.Which = getButton(int(e.data[2]))
+				mde.Clicks = clicks.Press(mde.Which, mde.Where, mde.When)
 				ec <- mde
 			case C.GMDMouseUp:
 				var mue wde.MouseUpEvent
+				mue.Source = w
+				mue.When = when
+				mue.Mods = mods
 				mue.Where.X = int(e.data[0])
 				mue.Where.Y = int(e.data[1])
 				mue.Which = getButton(int(e.data[2]))
+				mue.Clicks = clicks.Release(mue.Which)
 				ec <- mue
 			case C.GMDMouseDragged:
 				var mde wde.MouseDraggedEvent
+				mde.Source = w
+				mde.When = when
+				mde.Mods = mods
 				mde.Where.X = int(e.data[0])
 				mde.Where.Y = int(e.data[1])
 				mde.Which = getButton(int(e.data[2]))
 				ec <- mde
 			case C.GMDMouseMoved:
 				var me wde.MouseMovedEvent
+				me.Source = w
+				me.When = when
+				me.Mods = mods
 				me.Where.X = int(e.data[0])
 				me.Where.Y = int(e.data[1])
 				ec <- me
 			case C.GMDMouseEntered:
 				var me wde.MouseEnteredEvent
+				me.Source = w
+				me.When = when
+				me.Mods = mods
 				me.Where.X = int(e.data[0])
 				me.Where.Y = int(e.data[1])
 				ec <- me
 			case C.GMDMouseExited:
 				var me wde.MouseExitedEvent
+				me.Source = w
+				me.When = when
+				me.Mods = mods
 				me.Where.X = int(e.data[0])
 				me.Where.Y = int(e.data[1])
 				ec <- me
 			case C.GMDKeyDown:
 				var letter string
+				mods = modifiersForFlags(int(e.data[2]))
 				var ke wde.KeyEvent
+				ke.Source = w
+				ke.When = when
+				ke.Mods = mods
 				keycode := int(e.data[1])
 
 				blankLetter := containsInt(blankLetterCodes, keycode)
@@ -96,31 +171,100 @@ func (w *Window) EventChan() (events <-chan interface{}) {
 				}
 
 				ke.Key = keyMapping[keycode]
+				ke.Scancode = scancodes[keycode]
 
-				if !downKeys[ke.Key] {
-					ec <- wde.KeyDownEvent(ke)
-				}
+				// AppKit repeats key downs without key ups between them
+				repeat := downKeys[ke.Key]
+				ec <- wde.KeyDownEvent{KeyEvent: ke, Repeat: repeat}
 
 				downKeys[ke.Key] = true
 
 				ec <- wde.KeyTypedEvent{
 					KeyEvent: ke,
+					Repeat:   repeat,
 					Chord:    wde.ConstructChord(downKeys),
 					Glyph:    letter,
 				}
 
+				// gomacdraw passes on one character per key, so dead keys
+				// and input methods are not seen here
+				if r := rune(e.data[0]); letter != "" && mods&(wde.ModControl|wde.ModSuper) == 0 && isTextRune(r) {
+					var te wde.TextInputEvent
+					te.Source = w
+					te.When = when
+					te.Mods = mods
+					te.Text = string(r)
+					ec <- te
+				}
+
 			case C.GMDKeyUp:
+				mods = modifiersForFlags(int(e.data[2]))
 				var ke wde.KeyUpEvent
+				ke.Source = w
+				ke.When = when
+				ke.Mods = mods
 				ke.Key = keyMapping[int(e.data[1])]
+				ke.Scancode = scancodes[int(e.data[1])]
 				delete(downKeys, ke.Key)
 				ec <- ke
 			case C.GMDResize:
 				var re wde.ResizeEvent
+				re.Source = w
+				re.When = when
 				re.Width = int(e.data[0])
 				re.Height = int(e.data[1])
 				ec <- re
+			case C.WDEFocus:
+				var fe wde.FocusEvent
+				fe.Source = w
+				fe.When = when
+				fe.Gained = e.data[0] != 0
+				if !fe.Gained {
+					// the key releases will go to another window
+					downKeys = make(map[string]bool)
+				}
+				ec <- fe
+			case C.WDEMove:
+				var me wde.MoveEvent
+				me.Source = w
+				me.When = when
+				me.X = int(e.data[0])
+				me.Y = int(e.data[1])
+				ec <- me
+			case C.WDEScale:
+				if s := float64(e.data[0]) / 1000; s != scale {
+					scale = s
+					var se wde.ScaleChangedEvent
+					se.Source = w
+					se.When = when
+					se.Scale = scale
+					ec <- se
+				}
+			case C.WDEState:
+				if st := wde.WindowState(e.data[0]); st != state {
+					state = st
+					var se wde.WindowStateEvent
+					se.Source = w
+					se.When = when
+					se.State = state
+					ec <- se
+				}
+			case C.WDEScroll:
+				var se wde.ScrollEvent
+				se.Source = w
+				se.When = when
+				se.Mods = mods
+				se.Where.X = int(e.data[0])
+				se.Where.Y = int(e.data[1])
+				se.DeltaX = float64(e.data[2]) / 1000
+				se.DeltaY = float64(e.data[3]) / 1000
+				se.Precise = e.data[4] != 0
+				ec <- se
 			case C.GMDClose:
-				ec <- wde.CloseEvent{}
+				var ce wde.CloseEvent
+				ce.Source = w
+				ce.When = when
+				ec <- ce
 				break eventloop
 				return
 			}
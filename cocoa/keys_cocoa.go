@@ -100,6 +100,11 @@ var keyMapping = map[int]string{
 	105: wde.KeyF13,
 	107: wde.KeyF14,
 	113: wde.KeyF15,
+	106: wde.KeyF16,
+	64:  wde.KeyF17,
+	79:  wde.KeyF18,
+	80:  wde.KeyF19,
+	90:  wde.KeyF20,
 	123: wde.KeyLeftArrow,
 	124: wde.KeyRightArrow,
 	125: wde.KeyDownArrow,
@@ -116,19 +121,143 @@ var keyMapping = map[int]string{
 	114: wde.KeyInsert,
 	48:  wde.KeyTab,
 	49:  wde.KeySpace,
-	83:  wde.KeyPadHome, // keypad
-	84:  wde.KeyPadDown,
-	85:  wde.KeyPadNext,
-	86:  wde.KeyPadLeft,
-	87:  wde.KeyPadBegin,
-	88:  wde.KeyPadRight,
-	89:  wde.KeyPadEnd,
-	91:  wde.KeyPadUp,
-	92:  wde.KeyPadNext,
-	82:  wde.KeyPadInsert,
+	82:  wde.KeyPad0, // keypad
+	83:  wde.KeyPad1,
+	84:  wde.KeyPad2,
+	85:  wde.KeyPad3,
+	86:  wde.KeyPad4,
+	87:  wde.KeyPad5,
+	88:  wde.KeyPad6,
+	89:  wde.KeyPad7,
+	91:  wde.KeyPad8,
+	92:  wde.KeyPad9,
 	75:  wde.KeyPadSlash,
 	67:  wde.KeyPadStar,
 	78:  wde.KeyPadMinus,
 	69:  wde.KeyPadPlus,
 	65:  wde.KeyPadDot,
+	81:  wde.KeyPadEqual,
+	76:  wde.KeyPadEnter,
+	10:  wde.KeyIntlBackslash,
+	72:  wde.KeyVolumeUp,
+	73:  wde.KeyVolumeDown,
+	74:  wde.KeyMute,
+}
+
+// scancodes maps the virtual key codes of NSEvent's keyCode to where the
+// keys are.
+var scancodes = map[int]wde.Scancode{
+	0:   wde.ScancodeA,
+	1:   wde.ScancodeS,
+	2:   wde.ScancodeD,
+	3:   wde.ScancodeF,
+	4:   wde.ScancodeH,
+	5:   wde.ScancodeG,
+	6:   wde.ScancodeZ,
+	7:   wde.ScancodeX,
+	8:   wde.ScancodeC,
+	9:   wde.ScancodeV,
+	10:  wde.ScancodeNonUSBackslash,
+	11:  wde.ScancodeB,
+	12:  wde.ScancodeQ,
+	13:  wde.ScancodeW,
+	14:  wde.ScancodeE,
+	15:  wde.ScancodeR,
+	16:  wde.ScancodeY,
+	17:  wde.ScancodeT,
+	18:  wde.Scancode1,
+	19:  wde.Scancode2,
+	20:  wde.Scancode3,
+	21:  wde.Scancode4,
+	22:  wde.Scancode6,
+	23:  wde.Scancode5,
+	24:  wde.ScancodeEqual,
+	25:  wde.Scancode9,
+	26:  wde.Scancode7,
+	27:  wde.ScancodeMinus,
+	28:  wde.Scancode8,
+	29:  wde.Scancode0,
+	30:  wde.ScancodeRightBracket,
+	31:  wde.ScancodeO,
+	32:  wde.ScancodeU,
+	33:  wde.ScancodeLeftBracket,
+	34:  wde.ScancodeI,
+	35:  wde.ScancodeP,
+	36:  wde.ScancodeReturn,
+	37:  wde.ScancodeL,
+	38:  wde.ScancodeJ,
+	39:  wde.ScancodeQuote,
+	40:  wde.ScancodeK,
+	41:  wde.ScancodeSemicolon,
+	42:  wde.ScancodeBackslash,
+	43:  wde.ScancodeComma,
+	44:  wde.ScancodeSlash,
+	45:  wde.ScancodeN,
+	46:  wde.ScancodeM,
+	47:  wde.ScancodePeriod,
+	48:  wde.ScancodeTab,
+	49:  wde.ScancodeSpace,
+	50:  wde.ScancodeBackTick,
+	51:  wde.ScancodeBackspace,
+	53:  wde.ScancodeEscape,
+	54:  wde.ScancodeRightSuper,
+	55:  wde.ScancodeLeftSuper,
+	56:  wde.ScancodeLeftShift,
+	57:  wde.ScancodeCapsLock,
+	58:  wde.ScancodeLeftAlt,
+	59:  wde.ScancodeLeftControl,
+	60:  wde.ScancodeRightShift,
+	61:  wde.ScancodeRightAlt,
+	62:  wde.ScancodeRightControl,
+	64:  wde.ScancodeF17,
+	65:  wde.ScancodePadDot,
+	67:  wde.ScancodePadStar,
+	69:  wde.ScancodePadPlus,
+	71:  wde.ScancodeNumlock,
+	72:  wde.ScancodeVolumeUp,
+	73:  wde.ScancodeVolumeDown,
+	74:  wde.ScancodeMute,
+	75:  wde.ScancodePadSlash,
+	76:  wde.ScancodePadEnter,
+	78:  wde.ScancodePadMinus,
+	79:  wde.ScancodeF18,
+	80:  wde.ScancodeF19,
+	81:  wde.ScancodePadEqual,
+	82:  wde.ScancodePad0,
+	83:  wde.ScancodePad1,
+	84:  wde.ScancodePad2,
+	85:  wde.ScancodePad3,
+	86:  wde.ScancodePad4,
+	87:  wde.ScancodePad5,
+	88:  wde.ScancodePad6,
+	89:  wde.ScancodePad7,
+	90:  wde.ScancodeF20,
+	91:  wde.ScancodePad8,
+	92:  wde.ScancodePad9,
+	96:  wde.ScancodeF5,
+	97:  wde.ScancodeF6,
+	98:  wde.ScancodeF7,
+	99:  wde.ScancodeF3,
+	100: wde.ScancodeF8,
+	101: wde.ScancodeF9,
+	103: wde.ScancodeF11,
+	105: wde.ScancodeF13,
+	106: wde.ScancodeF16,
+	107: wde.ScancodeF14,
+	109: wde.ScancodeF10,
+	111: wde.ScancodeF12,
+	113: wde.ScancodeF15,
+	114: wde.ScancodeInsert,
+	115: wde.ScancodeHome,
+	116: wde.ScancodePrior,
+	117: wde.ScancodeDelete,
+	118: wde.ScancodeF4,
+	119: wde.ScancodeEnd,
+	120: wde.ScancodeF2,
+	121: wde.ScancodeNext,
+	122: wde.ScancodeF1,
+	123: wde.ScancodeLeftArrow,
+	124: wde.ScancodeRightArrow,
+	125: wde.ScancodeDownArrow,
+	126: wde.ScancodeUpArrow,
 }
@@ -0,0 +1,57 @@
+/*
+   Copyright 2012 the go.wde authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package cocoa
+
+import (
+	"github.com/skelterjohn/go.wde"
+	"testing"
+)
+
+func TestKeyMappingKnown(t *testing.T) {
+	for code, key := range keyMapping {
+		if !wde.KnownKey(key) {
+			t.Errorf("keyMapping[%d] = %q, which is not a wde key", code, key)
+		}
+	}
+}
+
+func TestScancodes(t *testing.T) {
+	for _, test := range []struct {
+		code int
+		want wde.Scancode
+	}{
+		{0, wde.ScancodeA},
+		{13, wde.ScancodeW},
+		{29, wde.Scancode0},
+		{36, wde.ScancodeReturn},
+		{50, wde.ScancodeBackTick},
+		{53, wde.ScancodeEscape},
+		{55, wde.ScancodeLeftSuper},
+		{64, wde.ScancodeF17},
+		{72, wde.ScancodeVolumeUp},
+		{74, wde.ScancodeMute},
+		{76, wde.ScancodePadEnter},
+		{82, wde.ScancodePad0},
+		{122, wde.ScancodeF1},
+		{126, wde.ScancodeUpArrow},
+		{127, 0},
+	} {
+		if got := scancodes[test.code]; got != test.want {
+			t.Errorf("scancodes[%d] = %#x, want %#x", test.code, got, test.want)
+		}
+	}
+}
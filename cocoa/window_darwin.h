@@ -0,0 +1,44 @@
+/*
+   Copyright 2012 the go.wde authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// What gomacdraw leaves out, done on the NSWindow that a GMDWindow, a
+// GoWindow, controls.
+
+#include "gomacdraw/gmd.h"
+
+// Events sent through the framework's event queue, beside its own GMD
+// codes.
+enum WDEEventCodes {
+    WDEFocus = 100,  // data[0] is 1 if the window gained the focus
+    WDEMove = 101,   // data[0], data[1] are the new position
+    WDEScale = 102,  // data[0] is the backing scale factor, times 1000
+    WDEState = 103,  // data[0] is the wde.WindowState
+    WDEScroll = 104, // data[0], data[1] are where, data[2], data[3] the
+                     // deltas times 1000, data[4] is 1 if precise
+};
+
+GMDWindow openHiddenWindow();
+void watchWindow(GMDWindow gmdw);
+
+void getWindowPosition(GMDWindow gmdw, int* x, int* y);
+void setWindowPosition(GMDWindow gmdw, int x, int y);
+void centerWindow(GMDWindow gmdw);
+double getWindowScale(GMDWindow gmdw);
+
+void setWindowFullscreen(GMDWindow gmdw, int fullscreen);
+void maximizeWindow(GMDWindow gmdw);
+void minimizeWindow(GMDWindow gmdw);
+void restoreWindow(GMDWindow gmdw);
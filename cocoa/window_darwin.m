@@ -0,0 +1,269 @@
+/*
+   Copyright 2012 the go.wde authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+#import <Cocoa/Cocoa.h>
+#include "window_darwin.h"
+
+// The framework's window is an EventWindow, whose queue getNextEvent reads.
+@interface NSWindow (GMDEventQueue)
+- (void)nq:(GMDEvent)e;
+- (void)setGw:(id)gw;
+@end
+
+// The framework's window controller is a GoWindow.
+@interface NSWindowController (GMDGoWindow)
+- (NSWindow*)eventWindow;
+@end
+
+// The values of wde.WindowState.
+enum {
+    stateNormal = 0,
+    stateMaximized = 1,
+    stateMinimized = 2,
+    stateFullscreen = 3,
+};
+
+static NSWindow* windowOf(GMDWindow gmdw) {
+    return [(NSWindowController*)gmdw window];
+}
+
+// onMain runs block on the main thread, where AppKit must be used, and
+// waits for it.
+static void onMain(void (^block)(void)) {
+    if ([NSThread isMainThread]) {
+        block();
+    } else {
+        dispatch_sync(dispatch_get_main_queue(), block);
+    }
+}
+
+static BOOL isFullscreen(NSWindow* win) {
+    return ([win styleMask] & NSFullScreenWindowMask) != 0;
+}
+
+// The screen's origin is the bottom left of the primary screen, with y
+// going up; wde's is its top left, with y going down.
+static CGFloat primaryHeight() {
+    NSArray* screens = [NSScreen screens];
+    if ([screens count] == 0) {
+        return 0;
+    }
+    return [[screens objectAtIndex:0] frame].size.height;
+}
+
+static void topLeft(NSWindow* win, int* x, int* y) {
+    NSRect frame = [win frame];
+    *x = (int)frame.origin.x;
+    *y = (int)(primaryHeight() - frame.origin.y - frame.size.height);
+}
+
+static int windowState(NSWindow* win) {
+    if ([win isMiniaturized]) {
+        return stateMinimized;
+    }
+    if (isFullscreen(win)) {
+        return stateFullscreen;
+    }
+    if ([win isZoomed]) {
+        return stateMaximized;
+    }
+    return stateNormal;
+}
+
+static void nqState(NSWindow* win) {
+    GMDEvent e = {0};
+    e.kind = WDEState;
+    e.data[0] = windowState(win);
+    [win nq:e];
+}
+
+// openHiddenWindow opens a window as the framework's openWindow does, but
+// leaves it off the screen until showWindow, so that it can be set up
+// first. It returns NULL if the framework's window cannot be loaded.
+GMDWindow openHiddenWindow() {
+    __block GMDWindow gmdw = NULL;
+    onMain(^{
+        NSBundle* fw = [NSBundle bundleWithIdentifier:@"John-Asmuth.gomacdraw"];
+        NSString* path = [[fw URLForResource:@"Window" withExtension:@"nib"] path];
+        Class gwClass = NSClassFromString(@"GoWindow");
+        if (path == nil || gwClass == Nil) {
+            return;
+        }
+        NSWindowController* gw = [gwClass alloc];
+        [gw initWithWindowNibPath:path owner:gw];
+        // loading the window fills in the controller's outlets
+        if ([gw window] == nil) {
+            [gw release];
+            return;
+        }
+        [[gw eventWindow] setGw:gw];
+        gmdw = (GMDWindow)gw;
+    });
+    return gmdw;
+}
+
+void watchWindow(GMDWindow gmdw) {
+    onMain(^{
+        NSWindow* win = windowOf(gmdw);
+        if (![win respondsToSelector:@selector(nq:)]) {
+            return;
+        }
+        NSNotificationCenter* nc = [NSNotificationCenter defaultCenter];
+        NSMutableArray* observers = [NSMutableArray array];
+        void (^observe)(NSString*, void (^)(NSNotification*)) = ^(NSString* name, void (^handler)(NSNotification*)) {
+            [observers addObject:[nc addObserverForName:name object:win queue:nil usingBlock:handler]];
+        };
+
+        observe(NSWindowDidBecomeKeyNotification, ^(NSNotification* n) {
+            GMDEvent e = {0};
+            e.kind = WDEFocus;
+            e.data[0] = 1;
+            [win nq:e];
+        });
+        observe(NSWindowDidResignKeyNotification, ^(NSNotification* n) {
+            GMDEvent e = {0};
+            e.kind = WDEFocus;
+            [win nq:e];
+        });
+        observe(NSWindowDidMoveNotification, ^(NSNotification* n) {
+            GMDEvent e = {0};
+            e.kind = WDEMove;
+            topLeft(win, &e.data[0], &e.data[1]);
+            [win nq:e];
+        });
+        observe(NSWindowDidChangeBackingPropertiesNotification, ^(NSNotification* n) {
+            GMDEvent e = {0};
+            e.kind = WDEScale;
+            e.data[0] = (int)([win backingScaleFactor] * 1000);
+            [win nq:e];
+        });
+        // the Go side drops states that have not changed, as zooming only
+        // shows as a resize
+        for (NSString* name in @[NSWindowDidMiniaturizeNotification,
+                                 NSWindowDidDeminiaturizeNotification,
+                                 NSWindowDidEnterFullScreenNotification,
+                                 NSWindowDidExitFullScreenNotification,
+                                 NSWindowDidResizeNotification]) {
+            observe(name, ^(NSNotification* n) {
+                nqState(win);
+            });
+        }
+
+        // EventWindow does not take scrollWheel:, so scrolling is caught
+        // before it is dispatched
+        id monitor = [NSEvent addLocalMonitorForEventsMatchingMask:NSScrollWheelMask handler:^NSEvent*(NSEvent* ev) {
+            if ([ev window] != win) {
+                return ev;
+            }
+            NSPoint loc = [ev locationInWindow];
+            GMDEvent e = {0};
+            e.kind = WDEScroll;
+            // the same reckoning as the framework's mouse events
+            e.data[0] = (int)loc.x;
+            e.data[1] = [win frame].size.height - (int)loc.y - (22 + 1);
+            // AppKit's deltaX is positive to the left; wde's to the right
+            e.data[2] = (int)(-[ev deltaX] * 1000);
+            e.data[3] = (int)([ev deltaY] * 1000);
+            e.data[4] = [ev hasPreciseScrollingDeltas] ? 1 : 0;
+            [win nq:e];
+            return ev;
+        }];
+
+        __block id closeObserver = [nc addObserverForName:NSWindowWillCloseNotification object:win queue:nil usingBlock:^(NSNotification* n) {
+            for (id o in observers) {
+                [nc removeObserver:o];
+            }
+            [nc removeObserver:closeObserver];
+            [NSEvent removeMonitor:monitor];
+        }];
+    });
+}
+
+void getWindowPosition(GMDWindow gmdw, int* x, int* y) {
+    onMain(^{
+        topLeft(windowOf(gmdw), x, y);
+    });
+}
+
+void setWindowPosition(GMDWindow gmdw, int x, int y) {
+    onMain(^{
+        [windowOf(gmdw) setFrameTopLeftPoint:NSMakePoint(x, primaryHeight() - y)];
+    });
+}
+
+void centerWindow(GMDWindow gmdw) {
+    onMain(^{
+        NSWindow* win = windowOf(gmdw);
+        NSScreen* screen = [win screen];
+        if (screen == nil) {
+            screen = [NSScreen mainScreen];
+        }
+        NSRect work = [screen visibleFrame];
+        NSRect frame = [win frame];
+        frame.origin.x = work.origin.x + (work.size.width - frame.size.width) / 2;
+        frame.origin.y = work.origin.y + (work.size.height - frame.size.height) / 2;
+        [win setFrameOrigin:frame.origin];
+    });
+}
+
+double getWindowScale(GMDWindow gmdw) {
+    __block double scale = 1;
+    onMain(^{
+        scale = [windowOf(gmdw) backingScaleFactor];
+    });
+    return scale;
+}
+
+void setWindowFullscreen(GMDWindow gmdw, int fullscreen) {
+    onMain(^{
+        NSWindow* win = windowOf(gmdw);
+        if (isFullscreen(win) != (fullscreen != 0)) {
+            [win setCollectionBehavior:[win collectionBehavior] | NSWindowCollectionBehaviorFullScreenPrimary];
+            [win toggleFullScreen:nil];
+        }
+    });
+}
+
+void maximizeWindow(GMDWindow gmdw) {
+    onMain(^{
+        NSWindow* win = windowOf(gmdw);
+        if (isFullscreen(win)) {
+            [win toggleFullScreen:nil];
+        }
+        if (![win isZoomed]) {
+            [win zoom:nil];
+        }
+    });
+}
+
+void minimizeWindow(GMDWindow gmdw) {
+    onMain(^{
+        [windowOf(gmdw) miniaturize:nil];
+    });
+}
+
+void restoreWindow(GMDWindow gmdw) {
+    onMain(^{
+        NSWindow* win = windowOf(gmdw);
+        if ([win isMiniaturized]) {
+            [win deminiaturize:nil];
+        } else if (isFullscreen(win)) {
+            [win toggleFullScreen:nil];
+        } else if ([win isZoomed]) {
+            [win zoom:nil];
+        }
+    });
+}
@@ -18,6 +18,8 @@ package wde
 
 import (
 	"image"
+	"reflect"
+	"time"
 )
 
 type Button int
@@ -26,14 +28,110 @@ const (
 	LeftButton Button = 1 << iota
 	MiddleButton
 	RightButton
-	WheelUpButton
-	WheelDownButton
 )
 
-type Event int
+/*
+Event is implemented by every event a Window sends on its EventChan, and
+only by the types in this package. Backends always send them as values,
+never as pointers, so a type switch on wde.MouseDownEvent, wde.KeyTypedEvent
+and so on sees the same events whichever backend is in use.
+*/
+type Event interface {
+	// Timestamp reports when the event happened, measured on a monotonic
+	// clock from an origin chosen by the backend. Only differences between
+	// timestamps are meaningful.
+	Timestamp() time.Duration
+	// Window reports the window the event was sent by.
+	Window() Window
+	// Modifiers reports the modifier keys held when the event happened.
+	Modifiers() Modifiers
+	isEvent()
+}
+
+// Modifiers is a bitmask of the modifier keys, and the lock keys, that
+// were held or active when an input event happened.
+type Modifiers uint
+
+const (
+	ModShift Modifiers = 1 << iota
+	ModControl
+	ModAlt
+	ModSuper
+	ModCapsLock
+	ModNumLock
+)
+
+// eventInfo holds what every event carries. Its fields are promoted into
+// each event type, so backends set them directly, as in e.Source = w.
+type eventInfo struct {
+	When   time.Duration
+	Source Window `json:"-"`
+	Mods   Modifiers
+}
+
+func (e eventInfo) Timestamp() time.Duration { return e.When }
+func (e eventInfo) Window() Window           { return e.Source }
+func (e eventInfo) Modifiers() Modifiers     { return e.Mods }
+func (e eventInfo) isEvent()                 {}
+
+func (e *eventInfo) setSource(w Window) { e.Source = w }
+
+/*
+WithWindow returns a copy of e whose Window method reports w. Code that
+wraps a Window uses it to pass the wrapped window's events on as its own.
+If e is a pointer to an event, the copy is of the event it points to, so
+the result is a value, as backends send. WithWindow returns nil if e is nil
+or a nil pointer.
+*/
+func WithWindow(e Event, w Window) Event {
+	v := reflect.ValueOf(e)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil
+	}
+	c := reflect.New(v.Type())
+	c.Elem().Set(v)
+	c.Interface().(interface {
+		setSource(Window)
+	}).setSource(w)
+	return c.Elem().Interface().(Event)
+}
+
+/*
+A MillisecondClock turns the wrapping 32-bit millisecond counters that X11,
+Win32 and SDL stamp their events with into event timestamps. The counters
+wrap about every 49 days; the timestamps keep counting. A MillisecondClock
+is meant to be used from a single goroutine.
+*/
+type MillisecondClock struct {
+	started bool
+	last    uint32
+	now     time.Duration
+}
+
+// Stamp returns the timestamp of an event that the window system stamped
+// with ms.
+func (c *MillisecondClock) Stamp(ms uint32) time.Duration {
+	if c.started {
+		c.now += time.Duration(int32(ms-c.last)) * time.Millisecond
+	} else {
+		c.now = time.Duration(ms) * time.Millisecond
+		c.started = true
+	}
+	c.last = ms
+	return c.now
+}
+
+// Now returns the most recent timestamp, for events that the window system
+// does not stamp itself.
+func (c *MillisecondClock) Now() time.Duration {
+	return c.now
+}
 
 type MouseEvent struct {
-	Event
+	eventInfo
 	Where image.Point
 }
 
@@ -45,6 +143,9 @@ type MouseMovedEvent struct {
 type MouseButtonEvent struct {
 	MouseEvent
 	Which Button
+	// Clicks is 1 for a single click, 2 for the second press of a double
+	// click, and so on. A release has the count of the press it ends.
+	Clicks int
 }
 
 type MouseDownEvent MouseButtonEvent
@@ -58,14 +159,38 @@ type MouseDraggedEvent struct {
 type MouseEnteredEvent MouseMovedEvent
 type MouseExitedEvent MouseMovedEvent
 
+/*
+ScrollEvent reports scrolling from a mouse wheel or a trackpad, with the
+pointer at Where. DeltaY is positive when scrolling up, away from the user,
+and DeltaX is positive when scrolling right. One notch of a mouse wheel is a
+delta of 1. Precise is set when the device scrolls smoothly, reporting
+fractions of a notch, as trackpads do.
+*/
+type ScrollEvent struct {
+	MouseEvent
+	DeltaX, DeltaY float64
+	Precise        bool
+}
+
 type KeyEvent struct {
+	eventInfo
 	Key string
+	// Scancode is the physical key, independent of the layout.
+	Scancode Scancode
+}
+
+// KeyDownEvent reports a key press. While a key is held down, the window
+// system's auto-repeat sends further KeyDownEvents with Repeat set.
+type KeyDownEvent struct {
+	KeyEvent
+	Repeat bool
 }
 
-type KeyDownEvent KeyEvent
 type KeyUpEvent KeyEvent
 type KeyTypedEvent struct {
 	KeyEvent
+	// Repeat is set when the key was typed by auto-repeat.
+	Repeat bool
 	/*
 		The glyph is the string corresponding to what the user wants to have typed in
 		whatever data entry is active.
@@ -79,8 +204,88 @@ type KeyTypedEvent struct {
 	Chord string
 }
 
+/*
+TextInputEvent reports text the user has entered, as UTF-8. Unlike a
+KeyTypedEvent's Glyph, it accounts for dead keys, compose sequences and
+input methods, so Text may hold a character no single key produces, or
+several characters at once. Text entry should insert Text and ignore
+Glyph. Keys pressed as shortcuts, with Control or Super held, produce no
+TextInputEvent.
+*/
+type TextInputEvent struct {
+	eventInfo
+	Text string
+}
+
+/*
+TextCompositionEvent reports the text being composed with a dead key or an
+input method, before it is committed. It should be shown at the insertion
+point, with the caret Cursor runes into Text, and replaced by each later
+TextCompositionEvent. An empty Text ends the composition, whether it was
+committed with a TextInputEvent or abandoned. Which compositions a backend
+sees varies: the xgb backend only composes dead keys, and cocoa none; see
+their package documentation.
+*/
+type TextCompositionEvent struct {
+	eventInfo
+	Text   string
+	Cursor int
+}
+
+// FocusEvent reports that the window gained or lost the keyboard focus.
+type FocusEvent struct {
+	eventInfo
+	Gained bool
+}
+
+/*
+ExposeEvent reports that parts of the window were uncovered or otherwise
+damaged. Rects are the damaged areas, in window coordinates. Backends
+repaint them from the last flushed frame, where they keep one, before
+sending the event, so most applications can ignore it; those that draw
+lazily can flush the damaged areas again.
+*/
+type ExposeEvent struct {
+	eventInfo
+	Rects []image.Rectangle
+}
+
 type ResizeEvent struct {
+	eventInfo
 	Width, Height int
 }
 
-type CloseEvent struct{}
+// MoveEvent reports that the window moved, whether the user or the program
+// moved it. X and Y are its new Position.
+type MoveEvent struct {
+	eventInfo
+	X, Y int
+}
+
+// ScaleChangedEvent reports that the window's Scale changed, usually
+// because it moved to a screen of another density. Scale is the new value.
+type ScaleChangedEvent struct {
+	eventInfo
+	Scale float64
+}
+
+// A WindowState is how a window is being shown.
+type WindowState int
+
+const (
+	StateNormal WindowState = iota
+	StateMaximized
+	StateMinimized
+	StateFullscreen
+)
+
+// WindowStateEvent reports that the window was maximized, minimized, made
+// fullscreen or restored, whether the user or the program did it.
+type WindowStateEvent struct {
+	eventInfo
+	State WindowState
+}
+
+type CloseEvent struct {
+	eventInfo
+}
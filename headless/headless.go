@@ -0,0 +1,494 @@
+/*
+   Copyright 2012 the go.wde authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+/*
+Package headless is a go.wde backend that never touches a display. Windows
+keep their screen in memory, remember every FlushImage call, and deliver
+whatever events are handed to Inject. It is meant for tests and CI, where
+the code sitting on top of wde.Window needs to run without X11, Win32 or
+Cocoa.
+
+	import _ "github.com/skelterjohn/go.wde/headless"
+*/
+package headless
+
+import (
+	"errors"
+	"github.com/skelterjohn/go.wde"
+	"image"
+	"image/draw"
+	"sync"
+)
+
+func init() {
+	wde.BackendNewWindow = func(width, height int) (w wde.Window, err error) {
+		w, err = NewWindow(width, height)
+		return
+	}
+	wde.BackendNewWindowWithOptions = func(opts wde.WindowOptions) (w wde.Window, err error) {
+		w, err = NewWindowWithOptions(opts)
+		return
+	}
+	ch := make(chan struct{}, 1)
+	wde.BackendRun = func() {
+		<-ch
+	}
+	wde.BackendStop = func() {
+		ch <- struct{}{}
+	}
+	wde.BackendScreens = func() ([]wde.Screen, error) {
+		return append([]wde.Screen(nil), Screens...), nil
+	}
+}
+
+// EventBuffer is the number of injected events a window holds before
+// Inject blocks waiting for the application to read them.
+var EventBuffer = 64
+
+var ErrClosed = errors.New("headless: window is closed")
+
+var ErrNoEvent = errors.New("headless: nil event")
+
+// Screens are the pretend monitors that wde.Screens reports and that
+// windows are centered on. Tests may replace them.
+var Screens = []wde.Screen{{
+	Name:        "headless",
+	Bounds:      image.Rect(0, 0, 1024, 768),
+	WorkArea:    image.Rect(0, 0, 1024, 768),
+	DPI:         96,
+	RefreshRate: 60,
+	Scale:       1,
+	Primary:     true,
+}}
+
+// A Flush records one call to Window.FlushImage.
+type Flush struct {
+	// Bounds are the rectangles passed to FlushImage, as given.
+	Bounds []image.Rectangle
+	// Image is a copy of the screen at the time of the flush.
+	Image *image.RGBA
+}
+
+type Window struct {
+	lock          sync.Mutex
+	title         string
+	width, height int
+	x, y          int
+	scale         float64
+	state         wde.WindowState
+	lockedSize    bool
+	options       wde.WindowOptions
+	shown         bool
+	closed        bool
+
+	buffer  Image
+	flushes []Flush
+	flushed chan struct{}
+
+	// queue holds the events waiting for room on events, in order, along
+	// with the channel to close once each is sent, if anyone is waiting.
+	// Events the window makes itself, as SetSize does, are queued without
+	// waiting, so that the goroutine reading events may call SetSize.
+	queue []queued
+	wake  chan struct{}
+
+	// sendLock keeps Close from closing events while send is sending on
+	// it; done releases any send or Inject still waiting once Close begins.
+	sendLock sync.RWMutex
+	done     chan struct{}
+	events   chan wde.Event
+}
+
+type queued struct {
+	e    wde.Event
+	sent chan struct{}
+}
+
+func NewWindow(width, height int) (w *Window, err error) {
+	return NewWindowWithOptions(wde.WindowOptions{Width: width, Height: height})
+}
+
+// NewWindowWithOptions creates a window with the title, position, locked
+// size and state of opts, without sending any events. The other options are
+// only kept, for Options to report.
+func NewWindowWithOptions(opts wde.WindowOptions) (w *Window, err error) {
+	w = &Window{
+		title:      opts.Title,
+		width:      opts.Width,
+		height:     opts.Height,
+		lockedSize: opts.FixedSize,
+		state:      opts.State,
+		options:    opts,
+		buffer:     Image{image.NewRGBA(image.Rect(0, 0, opts.Width, opts.Height))},
+		flushed:    make(chan struct{}),
+		wake:       make(chan struct{}, 1),
+		done:       make(chan struct{}),
+		events:     make(chan wde.Event, EventBuffer),
+	}
+	if opts.Position != nil {
+		w.x, w.y = opts.Position.X, opts.Position.Y
+	}
+	w.scale = w.screenScale()
+	go w.pump()
+	return
+}
+
+// Options returns the options the window was created with.
+func (w *Window) Options() wde.WindowOptions {
+	return w.options
+}
+
+func (w *Window) SetTitle(title string) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.title = title
+}
+
+// Title returns the title most recently given to SetTitle.
+func (w *Window) Title() string {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.title
+}
+
+// SetSize resizes the window and its screen, keeping whatever of the old
+// screen still fits, and then delivers a wde.ResizeEvent like a real
+// backend would once the window system had applied the change.
+func (w *Window) SetSize(width, height int) {
+	if !w.resize(width, height) {
+		return
+	}
+	var re wde.ResizeEvent
+	re.Source = w
+	re.Width, re.Height = width, height
+	w.post(re)
+}
+
+// resize resizes the window and its screen without telling the
+// application, and reports whether the size changed.
+func (w *Window) resize(width, height int) bool {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.closed || (width == w.width && height == w.height) {
+		return false
+	}
+	w.width, w.height = width, height
+	old := w.buffer
+	w.buffer = Image{image.NewRGBA(image.Rect(0, 0, width, height))}
+	draw.Draw(w.buffer.RGBA, old.Bounds(), old.RGBA, image.ZP, draw.Src)
+	return true
+}
+
+func (w *Window) Size() (width, height int) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.width, w.height
+}
+
+// SetPosition moves the window and then delivers a wde.MoveEvent, and a
+// wde.ScaleChangedEvent if the window moved to a screen of another Scale.
+func (w *Window) SetPosition(x, y int) {
+	w.lock.Lock()
+	if w.closed || (x == w.x && y == w.y) {
+		w.lock.Unlock()
+		return
+	}
+	w.x, w.y = x, y
+	scale := w.screenScale()
+	scaled := scale != w.scale
+	w.scale = scale
+	w.lock.Unlock()
+
+	var me wde.MoveEvent
+	me.Source = w
+	me.X, me.Y = x, y
+	w.post(me)
+	if scaled {
+		var se wde.ScaleChangedEvent
+		se.Source = w
+		se.Scale = scale
+		w.post(se)
+	}
+}
+
+func (w *Window) SetFullscreen(fullscreen bool) {
+	w.lock.Lock()
+	state := w.state
+	w.lock.Unlock()
+	if fullscreen {
+		w.setState(wde.StateFullscreen)
+	} else if state == wde.StateFullscreen {
+		w.setState(wde.StateNormal)
+	}
+}
+
+func (w *Window) Maximize() { w.setState(wde.StateMaximized) }
+func (w *Window) Minimize() { w.setState(wde.StateMinimized) }
+func (w *Window) Restore()  { w.setState(wde.StateNormal) }
+
+// State returns the window's state, as changed by SetFullscreen, Maximize,
+// Minimize and Restore. The window's size and position are left alone.
+func (w *Window) State() wde.WindowState {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.state
+}
+
+// setState changes the window's state and delivers a wde.WindowStateEvent,
+// if the state is a new one.
+func (w *Window) setState(state wde.WindowState) {
+	w.lock.Lock()
+	if w.closed || state == w.state {
+		w.lock.Unlock()
+		return
+	}
+	w.state = state
+	w.lock.Unlock()
+
+	var se wde.WindowStateEvent
+	se.Source = w
+	se.State = state
+	w.post(se)
+}
+
+func (w *Window) Scale() float64 {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.scale
+}
+
+// screenScale returns the Scale of whichever of Screens holds the middle of
+// the window. It is called with w.lock held.
+func (w *Window) screenScale() float64 {
+	s, ok := wde.ScreenAt(Screens, image.Pt(w.x+w.width/2, w.y+w.height/2))
+	if !ok || s.Scale == 0 {
+		return 1
+	}
+	return s.Scale
+}
+
+func (w *Window) Position() (x, y int) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.x, w.y
+}
+
+// Center moves the window to the middle of the work area of whichever of
+// Screens holds its middle.
+func (w *Window) Center() {
+	x, y := w.Position()
+	width, height := w.Size()
+	s, ok := wde.ScreenAt(Screens, image.Pt(x+width/2, y+height/2))
+	if !ok {
+		return
+	}
+	c := s.WorkArea.Min.Add(s.WorkArea.Size().Sub(image.Pt(width, height)).Div(2))
+	w.SetPosition(c.X, c.Y)
+}
+
+func (w *Window) LockSize(lock bool) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.lockedSize = lock
+}
+
+// SizeLocked reports the value most recently given to LockSize.
+func (w *Window) SizeLocked() bool {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.lockedSize
+}
+
+func (w *Window) Show() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.shown = true
+}
+
+// Shown reports whether Show has been called.
+func (w *Window) Shown() bool {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.shown
+}
+
+func (w *Window) Screen() (im wde.Image) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.buffer
+}
+
+func (w *Window) FlushImage(bounds ...image.Rectangle) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.closed {
+		return
+	}
+
+	f := Flush{
+		Bounds: append([]image.Rectangle(nil), bounds...),
+		Image:  image.NewRGBA(w.buffer.Bounds()),
+	}
+	copy(f.Image.Pix, w.buffer.Pix)
+	w.flushes = append(w.flushes, f)
+
+	close(w.flushed)
+	w.flushed = make(chan struct{})
+}
+
+// Flushes returns every flush recorded so far, oldest first.
+func (w *Window) Flushes() []Flush {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return append([]Flush(nil), w.flushes...)
+}
+
+// LastFlush returns the most recent flush, and false if there has been none.
+func (w *Window) LastFlush() (f Flush, ok bool) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if len(w.flushes) == 0 {
+		return
+	}
+	return w.flushes[len(w.flushes)-1], true
+}
+
+// Flushed returns a channel that is closed the next time FlushImage is
+// called.
+func (w *Window) Flushed() <-chan struct{} {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.flushed
+}
+
+/*
+Inject delivers e on the window's event channel, as if a backend had
+translated it from the window system. The event's Window method will
+report w. A pointer to an event is delivered as the event it points to.
+Injecting a wde.ResizeEvent resizes the window first, as SetSize would, so
+that the screen is the size the event reports. Inject returns once e is on
+the channel, so it blocks while EventBuffer events are waiting to be read.
+*/
+func (w *Window) Inject(e wde.Event) (err error) {
+	if e = wde.WithWindow(e, w); e == nil {
+		return ErrNoEvent
+	}
+	if re, ok := e.(wde.ResizeEvent); ok {
+		w.resize(re.Width, re.Height)
+	}
+	sent := make(chan struct{})
+	if !w.enqueue(e, sent) {
+		return ErrClosed
+	}
+	select {
+	case <-sent:
+	case <-w.done:
+		err = ErrClosed
+	}
+	return
+}
+
+// post queues an event the window made itself, without waiting for it to be
+// sent.
+func (w *Window) post(e wde.Event) {
+	w.enqueue(e, nil)
+}
+
+// enqueue adds e to the queue, reporting false if the window is closed.
+func (w *Window) enqueue(e wde.Event, sent chan struct{}) bool {
+	w.lock.Lock()
+	if w.closed {
+		w.lock.Unlock()
+		return false
+	}
+	w.queue = append(w.queue, queued{e, sent})
+	w.lock.Unlock()
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+// pump moves queued events onto the event channel until the window closes.
+func (w *Window) pump() {
+	for {
+		w.lock.Lock()
+		if len(w.queue) == 0 {
+			w.lock.Unlock()
+			select {
+			case <-w.wake:
+				continue
+			case <-w.done:
+				return
+			}
+		}
+		q := w.queue[0]
+		w.queue = w.queue[1:]
+		w.lock.Unlock()
+
+		if !w.send(q.e) {
+			return
+		}
+		if q.sent != nil {
+			close(q.sent)
+		}
+	}
+}
+
+func (w *Window) send(e wde.Event) bool {
+	w.sendLock.RLock()
+	defer w.sendLock.RUnlock()
+	select {
+	case <-w.done:
+		return false
+	default:
+	}
+	select {
+	case w.events <- e:
+		return true
+	case <-w.done:
+		return false
+	}
+}
+
+func (w *Window) EventChan() (events <-chan wde.Event) {
+	return w.events
+}
+
+// Close closes the window and its event channel.
+func (w *Window) Close() (err error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.closed {
+		return
+	}
+	w.closed = true
+	close(w.done)
+
+	w.sendLock.Lock()
+	close(w.events)
+	w.sendLock.Unlock()
+	return
+}
+
+type Image struct {
+	*image.RGBA
+}
+
+func (im Image) CopyRGBA(src *image.RGBA, bounds image.Rectangle) {
+	draw.Draw(im.RGBA, bounds, src, image.Point{0, 0}, draw.Src)
+}
@@ -0,0 +1,112 @@
+/*
+   Copyright 2012 the go.wde authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package headless
+
+import (
+	"github.com/skelterjohn/go.wde"
+	"testing"
+	"time"
+)
+
+// The goroutine reading events must be able to change the window while the
+// event buffer is full, as applications do in response to an event.
+func TestSetSizeWithFullBuffer(t *testing.T) {
+	w, err := NewWindow(10, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	for i := 0; i < EventBuffer; i++ {
+		if err := w.Inject(wde.FocusEvent{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		w.SetSize(20, 20)
+		w.SetPosition(5, 5)
+		w.Maximize()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("SetSize blocked on a full event buffer")
+	}
+
+	// the window's own events come after those already injected, in order
+	var got []wde.Event
+	for len(got) < EventBuffer+3 {
+		select {
+		case e := <-w.EventChan():
+			got = append(got, e)
+		case <-time.After(time.Second):
+			t.Fatalf("got %d events, want %d", len(got), EventBuffer+3)
+		}
+	}
+	tail := got[EventBuffer:]
+	if _, ok := tail[0].(wde.ResizeEvent); !ok {
+		t.Errorf("got %T, want wde.ResizeEvent", tail[0])
+	}
+	if _, ok := tail[1].(wde.MoveEvent); !ok {
+		t.Errorf("got %T, want wde.MoveEvent", tail[1])
+	}
+	if _, ok := tail[2].(wde.WindowStateEvent); !ok {
+		t.Errorf("got %T, want wde.WindowStateEvent", tail[2])
+	}
+}
+
+func TestInjectAfterClose(t *testing.T) {
+	w, err := NewWindow(10, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	if err := w.Inject(wde.FocusEvent{}); err != ErrClosed {
+		t.Errorf("got %v, want ErrClosed", err)
+	}
+	w.SetSize(20, 20)
+	if _, ok := <-w.EventChan(); ok {
+		t.Error("event sent after Close")
+	}
+}
+
+// Tests may inject pointers to events; the application still sees values.
+func TestInjectPointer(t *testing.T) {
+	w, err := NewWindow(10, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	go w.Inject(&wde.KeyDownEvent{KeyEvent: wde.KeyEvent{Key: wde.KeyA}})
+	e, ok := (<-w.EventChan()).(wde.KeyDownEvent)
+	if !ok {
+		t.Fatalf("got %T, want wde.KeyDownEvent", e)
+	}
+	if e.Key != wde.KeyA || e.Window() != w {
+		t.Errorf("got %+v from %v, want %s from %v", e, e.Window(), wde.KeyA, w)
+	}
+
+	var nilEvent *wde.KeyDownEvent
+	if err := w.Inject(nilEvent); err != ErrNoEvent {
+		t.Errorf("got %v, want ErrNoEvent", err)
+	}
+	if err := w.Inject(nil); err != ErrNoEvent {
+		t.Errorf("got %v, want ErrNoEvent", err)
+	}
+}
@@ -22,114 +22,202 @@ import (
 )
 
 const (
-	KeyFunction     = "function"
-	KeyLeftSuper    = "left_super"
-	KeyRightSuper   = "right_super"
-	KeyLeftAlt      = "left_alt"
-	KeyRightAlt     = "right_alt"
-	KeyLeftControl  = "left_control"
-	KeyRightControl = "right_control"
-	KeyLeftShift    = "left_shift"
-	KeyRightShift   = "right_shift"
-	KeyUpArrow      = "up_arrow"
-	KeyDownArrow    = "down_arrow"
-	KeyLeftArrow    = "left_arrow"
-	KeyRightArrow   = "right_arrow"
-	KeyInsert       = "insert"
-	KeyTab          = "tab"
-	KeySpace        = "space"
-	KeyA            = "a"
-	KeyB            = "b"
-	KeyC            = "c"
-	KeyD            = "d"
-	KeyE            = "e"
-	KeyF            = "f"
-	KeyG            = "g"
-	KeyH            = "h"
-	KeyI            = "i"
-	KeyJ            = "j"
-	KeyK            = "k"
-	KeyL            = "l"
-	KeyM            = "m"
-	KeyN            = "n"
-	KeyO            = "o"
-	KeyP            = "p"
-	KeyQ            = "q"
-	KeyR            = "r"
-	KeyS            = "s"
-	KeyT            = "t"
-	KeyU            = "u"
-	KeyV            = "v"
-	KeyW            = "w"
-	KeyX            = "x"
-	KeyY            = "y"
-	KeyZ            = "z"
-	Key1            = "1"
-	Key2            = "2"
-	Key3            = "3"
-	Key4            = "4"
-	Key5            = "5"
-	Key6            = "6"
-	Key7            = "7"
-	Key8            = "8"
-	Key9            = "9"
-	Key0            = "0"
-	KeyPadEnd       = "kp_end"
-	KeyPadDown      = "kp_down"
-	KeyPadNext      = "kp_next"
-	KeyPadLeft      = "kp_left"
-	KeyPadBegin     = "kp_begin"
-	KeyPadRight     = "kp_right"
-	KeyPadHome      = "kp_home"
-	KeyPadUp        = "kp_up"
-	KeyPadPrior     = "kp_prior"
-	KeyPadInsert    = "kpInsert"
-	KeyPadSlash     = "kpSlash"
-	KeyPadStar      = "kpStar"
-	KeyPadMinus     = "kpMinus"
-	KeyPadPlus      = "kpPlus"
-	KeyPadDot       = "kpDot"
-	KeyPadEqual     = "kpEqual"
-	KeyPadEnter     = "kpEnter"
-	KeyBackTick     = "`"
-	KeyF1           = "f1"
-	KeyF2           = "f2"
-	KeyF3           = "f3"
-	KeyF4           = "f4"
-	KeyF5           = "f5"
-	KeyF6           = "f6"
-	KeyF7           = "f7"
-	KeyF8           = "f8"
-	KeyF9           = "f9"
-	KeyF10          = "f10"
-	KeyF11          = "f11"
-	KeyF12          = "f12"
-	KeyF13          = "f13"
-	KeyF14          = "f14"
-	KeyF15          = "f15"
-	KeyF16          = "f16"
-	KeyMinus        = "-"
-	KeyEqual        = "="
-	KeyLeftBracket  = "["
-	KeyRightBracket = "]"
-	KeyBackslash    = `\`
-	KeySemicolon    = ";"
-	KeyQuote        = "'"
-	KeyComma        = ","
-	KeyPeriod       = "."
-	KeySlash        = "/"
-	KeyReturn       = "return"
-	KeyEscape       = "escape"
-	KeyNumlock      = "numlock"
-	KeyDelete       = "delete"
-	KeyBackspace    = "backspace"
-	KeyHome         = "home"
-	KeyEnd          = "end"
-	KeyPrior        = "prior"
-	KeyNext         = "next"
-	KeyCapsLock     = "caps"
+	KeyFunction      = "function"
+	KeyLeftSuper     = "left_super"
+	KeyRightSuper    = "right_super"
+	KeyLeftAlt       = "left_alt"
+	KeyRightAlt      = "right_alt"
+	KeyLeftControl   = "left_control"
+	KeyRightControl  = "right_control"
+	KeyLeftShift     = "left_shift"
+	KeyRightShift    = "right_shift"
+	KeyUpArrow       = "up_arrow"
+	KeyDownArrow     = "down_arrow"
+	KeyLeftArrow     = "left_arrow"
+	KeyRightArrow    = "right_arrow"
+	KeyInsert        = "insert"
+	KeyTab           = "tab"
+	KeySpace         = "space"
+	KeyA             = "a"
+	KeyB             = "b"
+	KeyC             = "c"
+	KeyD             = "d"
+	KeyE             = "e"
+	KeyF             = "f"
+	KeyG             = "g"
+	KeyH             = "h"
+	KeyI             = "i"
+	KeyJ             = "j"
+	KeyK             = "k"
+	KeyL             = "l"
+	KeyM             = "m"
+	KeyN             = "n"
+	KeyO             = "o"
+	KeyP             = "p"
+	KeyQ             = "q"
+	KeyR             = "r"
+	KeyS             = "s"
+	KeyT             = "t"
+	KeyU             = "u"
+	KeyV             = "v"
+	KeyW             = "w"
+	KeyX             = "x"
+	KeyY             = "y"
+	KeyZ             = "z"
+	Key1             = "1"
+	Key2             = "2"
+	Key3             = "3"
+	Key4             = "4"
+	Key5             = "5"
+	Key6             = "6"
+	Key7             = "7"
+	Key8             = "8"
+	Key9             = "9"
+	Key0             = "0"
+	KeyPadEnd        = "kp_end"
+	KeyPadDown       = "kp_down"
+	KeyPadNext       = "kp_next"
+	KeyPadLeft       = "kp_left"
+	KeyPadBegin      = "kp_begin"
+	KeyPadRight      = "kp_right"
+	KeyPadHome       = "kp_home"
+	KeyPadUp         = "kp_up"
+	KeyPadPrior      = "kp_prior"
+	KeyPadInsert     = "kp_insert"
+	KeyPadDelete     = "kp_delete"
+	KeyPadSlash      = "kp_slash"
+	KeyPadStar       = "kp_star"
+	KeyPadMinus      = "kp_minus"
+	KeyPadPlus       = "kp_plus"
+	KeyPadDot        = "kp_dot"
+	KeyPadComma      = "kp_comma"
+	KeyPadEqual      = "kp_equal"
+	KeyPadEnter      = "kp_enter"
+	KeyPad0          = "kp_0"
+	KeyPad1          = "kp_1"
+	KeyPad2          = "kp_2"
+	KeyPad3          = "kp_3"
+	KeyPad4          = "kp_4"
+	KeyPad5          = "kp_5"
+	KeyPad6          = "kp_6"
+	KeyPad7          = "kp_7"
+	KeyPad8          = "kp_8"
+	KeyPad9          = "kp_9"
+	KeyBackTick      = "`"
+	KeyF1            = "f1"
+	KeyF2            = "f2"
+	KeyF3            = "f3"
+	KeyF4            = "f4"
+	KeyF5            = "f5"
+	KeyF6            = "f6"
+	KeyF7            = "f7"
+	KeyF8            = "f8"
+	KeyF9            = "f9"
+	KeyF10           = "f10"
+	KeyF11           = "f11"
+	KeyF12           = "f12"
+	KeyF13           = "f13"
+	KeyF14           = "f14"
+	KeyF15           = "f15"
+	KeyF16           = "f16"
+	KeyF17           = "f17"
+	KeyF18           = "f18"
+	KeyF19           = "f19"
+	KeyF20           = "f20"
+	KeyF21           = "f21"
+	KeyF22           = "f22"
+	KeyF23           = "f23"
+	KeyF24           = "f24"
+	KeyMinus         = "-"
+	KeyEqual         = "="
+	KeyLeftBracket   = "["
+	KeyRightBracket  = "]"
+	KeyBackslash     = `\`
+	KeySemicolon     = ";"
+	KeyQuote         = "'"
+	KeyComma         = ","
+	KeyPeriod        = "."
+	KeySlash         = "/"
+	KeyIntlBackslash = "intl_backslash"
+	KeyReturn        = "return"
+	KeyEscape        = "escape"
+	KeyNumlock       = "numlock"
+	KeyDelete        = "delete"
+	KeyBackspace     = "backspace"
+	KeyHome          = "home"
+	KeyEnd           = "end"
+	KeyPrior         = "prior"
+	KeyNext          = "next"
+	KeyCapsLock      = "caps"
+	KeyScrollLock    = "scroll_lock"
+	KeyPause         = "pause"
+	KeyPrintScreen   = "print_screen"
+	KeyMenu          = "menu"
+	KeyMute          = "mute"
+	KeyVolumeDown    = "volume_down"
+	KeyVolumeUp      = "volume_up"
+	KeyMediaPlay     = "media_play"
+	KeyMediaStop     = "media_stop"
+	KeyMediaPrev     = "media_prev"
+	KeyMediaNext     = "media_next"
 )
 
+// knownKeys lists every key constant above. Each backend reports keys
+// from this list wherever it can tell what the key is.
+var knownKeys = []string{
+	KeyFunction, KeyLeftSuper, KeyRightSuper, KeyLeftAlt, KeyRightAlt,
+	KeyLeftControl, KeyRightControl, KeyLeftShift, KeyRightShift,
+	KeyUpArrow, KeyDownArrow, KeyLeftArrow, KeyRightArrow, KeyInsert,
+	KeyTab, KeySpace, KeyA, KeyB, KeyC, KeyD, KeyE, KeyF, KeyG, KeyH,
+	KeyI, KeyJ, KeyK, KeyL, KeyM, KeyN, KeyO, KeyP, KeyQ, KeyR, KeyS,
+	KeyT, KeyU, KeyV, KeyW, KeyX, KeyY, KeyZ, Key1, Key2, Key3, Key4,
+	Key5, Key6, Key7, Key8, Key9, Key0, KeyPadEnd, KeyPadDown,
+	KeyPadNext, KeyPadLeft, KeyPadBegin, KeyPadRight, KeyPadHome,
+	KeyPadUp, KeyPadPrior, KeyPadInsert, KeyPadDelete, KeyPadSlash,
+	KeyPadStar, KeyPadMinus, KeyPadPlus, KeyPadDot, KeyPadComma,
+	KeyPadEqual, KeyPadEnter, KeyPad0, KeyPad1, KeyPad2, KeyPad3,
+	KeyPad4, KeyPad5, KeyPad6, KeyPad7, KeyPad8, KeyPad9, KeyBackTick,
+	KeyF1, KeyF2, KeyF3, KeyF4, KeyF5, KeyF6, KeyF7, KeyF8, KeyF9,
+	KeyF10, KeyF11, KeyF12, KeyF13, KeyF14, KeyF15, KeyF16, KeyF17,
+	KeyF18, KeyF19, KeyF20, KeyF21, KeyF22, KeyF23, KeyF24, KeyMinus,
+	KeyEqual, KeyLeftBracket, KeyRightBracket, KeyBackslash,
+	KeySemicolon, KeyQuote, KeyComma, KeyPeriod, KeySlash,
+	KeyIntlBackslash, KeyReturn, KeyEscape, KeyNumlock, KeyDelete,
+	KeyBackspace, KeyHome, KeyEnd, KeyPrior, KeyNext, KeyCapsLock,
+	KeyScrollLock, KeyPause, KeyPrintScreen, KeyMenu, KeyMute,
+	KeyVolumeDown, KeyVolumeUp, KeyMediaPlay, KeyMediaStop, KeyMediaPrev,
+	KeyMediaNext,
+}
+
+var knownKeySet map[string]bool
+
+// KnownKeys returns every key constant declared in this package.
+func KnownKeys() (keys []string) {
+	return append(keys, knownKeys...)
+}
+
+// KnownKey reports whether key is one of the key constants declared in this
+// package, rather than a name a backend made up for a key wde does not know.
+func KnownKey(key string) bool {
+	return knownKeySet[key]
+}
+
+// ModifierForKey returns the modifier that holding key sets, or 0 if key is
+// not a modifier key.
+func ModifierForKey(key string) Modifiers {
+	switch key {
+	case KeyLeftShift, KeyRightShift:
+		return ModShift
+	case KeyLeftControl, KeyRightControl:
+		return ModControl
+	case KeyLeftAlt, KeyRightAlt:
+		return ModAlt
+	case KeyLeftSuper, KeyRightSuper:
+		return ModSuper
+	}
+	return 0
+}
+
 var chordPrecedence = []string{
 	"super",
 	"shift",
@@ -141,6 +229,11 @@ var chordPrecedence = []string{
 var chordIndices map[string]int
 
 func init() {
+	knownKeySet = map[string]bool{}
+	for _, k := range knownKeys {
+		knownKeySet[k] = true
+	}
+
 	chordIndices = map[string]int{}
 	for i, k := range chordPrecedence {
 		// we give these negative values so that when a lookup is done on something
@@ -170,14 +263,14 @@ func (c ChordSorter) Less(i, j int) (less bool) {
 	return ip < jp
 }
 
+// ConstructChord joins the held keys into a chord in ChordSorter order. The
+// modifier keys are named without their side, so left_shift and right_shift
+// both appear as shift; other keys, like the arrows, keep their names.
 func ConstructChord(keys map[string]bool) (chord string) {
 	unikeys := map[string]bool{}
 	for key := range keys {
-		if strings.HasPrefix(key, "left_") {
-			key = key[5:]
-		}
-		if strings.HasPrefix(key, "right_") {
-			key = key[6:]
+		if ModifierForKey(key) != 0 {
+			key = strings.TrimPrefix(strings.TrimPrefix(key, "left_"), "right_")
 		}
 		unikeys[key] = true
 	}
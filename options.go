@@ -0,0 +1,87 @@
+/*
+   Copyright 2012 the go.wde authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package wde
+
+import (
+	"image"
+)
+
+/*
+WindowOptions describe a window for NewWindowWithOptions. The zero value of
+each field leaves the backend's default, so only Width and Height need be
+set. Backends apply the options as they create the window, before it is
+shown, except for those their window system lacks, which they ignore.
+*/
+type WindowOptions struct {
+	Width, Height int
+	Title         string
+	// Position is where the window's top-left corner goes, measured as
+	// SetPosition measures it. If it is nil the window system chooses.
+	Position *image.Point
+	// MinSize and MaxSize limit the sizes the user can give the window.
+	// A zero dimension is not limited.
+	MinSize, MaxSize image.Point
+	// FixedSize keeps the user from resizing the window, as LockSize does.
+	FixedSize bool
+	// Undecorated leaves off the frame and title bar.
+	Undecorated bool
+	// AlwaysOnTop keeps the window above all others that do not have it.
+	AlwaysOnTop bool
+	// Parent, if not nil, is a window from the same backend that the window
+	// belongs to, as a dialog does. The window stays above its parent.
+	Parent Window
+	// State is how the window is first shown.
+	State WindowState
+}
+
+/*
+NewWindowWithOptions creates a window as opts describe, so that it does not
+flicker or jump about as it is changed once it is on the screen.
+*/
+func NewWindowWithOptions(opts WindowOptions) (Window, error) {
+	return BackendNewWindowWithOptions(opts)
+}
+
+/*
+BackendNewWindowWithOptions is set by backends that can apply options as
+they create a window. By default a window is created with BackendNewWindow
+and then given what options the Window interface can set.
+*/
+var BackendNewWindowWithOptions = func(opts WindowOptions) (w Window, err error) {
+	w, err = BackendNewWindow(opts.Width, opts.Height)
+	if err != nil {
+		return
+	}
+	if opts.Title != "" {
+		w.SetTitle(opts.Title)
+	}
+	if opts.Position != nil {
+		w.SetPosition(opts.Position.X, opts.Position.Y)
+	}
+	if opts.FixedSize {
+		w.LockSize(true)
+	}
+	switch opts.State {
+	case StateMaximized:
+		w.Maximize()
+	case StateMinimized:
+		w.Minimize()
+	case StateFullscreen:
+		w.SetFullscreen(true)
+	}
+	return
+}
@@ -0,0 +1,269 @@
+/*
+   Copyright 2012 the go.wde authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+/*
+Package record saves a window's events to a file and plays them back, so
+that a session can be reproduced, whether to chase down a bug a user ran
+into or to drive a test.
+
+A recording is JSON, one value per line. The first line is a Header giving
+the format version and the size the window had when recording began; each
+line after it is one event, as its type name and its fields:
+
+	{"Format":"wde-events","Version":1,"Width":400,"Height":300}
+	{"Type":"MouseDownEvent","Event":{"When":1520000000,"Mods":0,"Where":{"X":10,"Y":12},"Which":1}}
+
+Recording an application's events takes one line in its event loop:
+
+	rec, err := record.NewRecorder(f, 400, 300)
+	...
+	for e := range rec.Tee(w.EventChan()) {
+		...
+	}
+
+To replay them, open the recording, make a window of the recorded size
+(usually with the headless backend) and hand it to Replay:
+
+	r, err := record.NewReader(f)
+	...
+	w, err := headless.NewWindow(r.Header.Width, r.Header.Height)
+	go app.Run(w)
+	err = r.Replay(w, false)
+*/
+package record
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/skelterjohn/go.wde"
+	"io"
+	"reflect"
+	"sync"
+	"time"
+)
+
+const (
+	Format  = "wde-events"
+	Version = 1
+)
+
+var ErrFormat = errors.New("record: not a wde event recording")
+
+// A Header begins every recording.
+type Header struct {
+	Format  string
+	Version int
+	// Width and Height are the size of the window when recording began.
+	// Later sizes are recorded as ResizeEvents.
+	Width, Height int
+}
+
+// eventTypes are the events a recording can hold, by type name.
+var eventTypes = map[string]reflect.Type{}
+
+func init() {
+	for _, e := range []wde.Event{
+		wde.MouseMovedEvent{},
+		wde.MouseDownEvent{},
+		wde.MouseUpEvent{},
+		wde.MouseDraggedEvent{},
+		wde.MouseEnteredEvent{},
+		wde.MouseExitedEvent{},
+		wde.ScrollEvent{},
+		wde.KeyDownEvent{},
+		wde.KeyUpEvent{},
+		wde.KeyTypedEvent{},
+		wde.TextInputEvent{},
+		wde.TextCompositionEvent{},
+		wde.FocusEvent{},
+		wde.ExposeEvent{},
+		wde.ResizeEvent{},
+		wde.MoveEvent{},
+		wde.ScaleChangedEvent{},
+		wde.WindowStateEvent{},
+		wde.CloseEvent{},
+	} {
+		t := reflect.TypeOf(e)
+		eventTypes[t.Name()] = t
+	}
+}
+
+type line struct {
+	Type  string
+	Event json.RawMessage
+}
+
+/*
+A Recorder writes events to a recording. Its methods may be called from
+any goroutine. Events are written as they are recorded; if the writer
+buffers, flushing it is up to the caller.
+*/
+type Recorder struct {
+	lock sync.Mutex
+	enc  *json.Encoder
+	err  error
+}
+
+// NewRecorder writes the header of a recording of a width by height window
+// to w, and returns a Recorder for its events.
+func NewRecorder(w io.Writer, width, height int) (r *Recorder, err error) {
+	enc := json.NewEncoder(w)
+	err = enc.Encode(Header{
+		Format:  Format,
+		Version: Version,
+		Width:   width,
+		Height:  height,
+	})
+	if err != nil {
+		return
+	}
+	r = &Recorder{enc: enc}
+	return
+}
+
+// Record writes e to the recording. Once writing has failed, every later
+// call returns the same error.
+func (r *Recorder) Record(e wde.Event) (err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+
+	if e == nil {
+		return errors.New("record: cannot record a nil event")
+	}
+	t := reflect.TypeOf(e)
+	if eventTypes[t.Name()] != t {
+		return fmt.Errorf("record: cannot record a %v", t)
+	}
+	raw, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	r.err = r.enc.Encode(line{Type: t.Name(), Event: raw})
+	return r.err
+}
+
+/*
+Tee records every event read from events, usually a window's EventChan,
+and passes it on. The returned channel is closed once events is. Events
+keep flowing if recording fails; Err reports why it did.
+*/
+func (r *Recorder) Tee(events <-chan wde.Event) <-chan wde.Event {
+	out := make(chan wde.Event)
+	go func() {
+		defer close(out)
+		for e := range events {
+			r.Record(e)
+			out <- e
+		}
+	}()
+	return out
+}
+
+// Err returns the error that stopped the recording, if any.
+func (r *Recorder) Err() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.err
+}
+
+// A Reader reads the events of a recording back.
+type Reader struct {
+	Header Header
+
+	dec *json.Decoder
+}
+
+// NewReader reads the header of the recording in r. It returns ErrFormat
+// if r does not hold a recording, and an error if the recording is of a
+// later version than this package knows.
+func NewReader(r io.Reader) (rd *Reader, err error) {
+	rd = &Reader{dec: json.NewDecoder(bufio.NewReader(r))}
+	if err = rd.dec.Decode(&rd.Header); err != nil || rd.Header.Format != Format {
+		return nil, ErrFormat
+	}
+	if rd.Header.Version > Version {
+		return nil, fmt.Errorf("record: recording is version %d, newer than %d", rd.Header.Version, Version)
+	}
+	return
+}
+
+// Next returns the next event of the recording, or io.EOF once there are
+// none left. The event's Window method reports nil.
+func (rd *Reader) Next() (e wde.Event, err error) {
+	var l line
+	if err = rd.dec.Decode(&l); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			err = fmt.Errorf("record: recording is truncated")
+		}
+		return
+	}
+	t, ok := eventTypes[l.Type]
+	if !ok {
+		err = fmt.Errorf("record: unknown event type %q", l.Type)
+		return
+	}
+	v := reflect.New(t)
+	if err = json.Unmarshal(l.Event, v.Interface()); err != nil {
+		return
+	}
+	e = v.Elem().Interface().(wde.Event)
+	return
+}
+
+// A Target is a window that recorded events can be replayed to, such as a
+// headless window.
+type Target interface {
+	// Inject delivers e to the application as though the window sent it.
+	// Given a wde.ResizeEvent, it resizes the window to match first, so
+	// that the window is already the recorded size when the application
+	// hears of it.
+	Inject(e wde.Event) error
+}
+
+/*
+Replay passes the rest of the recording's events to t, stopping early if
+Inject returns an error. With realtime set, Replay waits between events for
+as long as passed between them when they were recorded; otherwise it passes
+them on as fast as t takes them.
+
+A wdetest.Window is not a Target, as its Inject cannot fail. To replay
+events through one, pass Replay the headless window it wraps.
+*/
+func (rd *Reader) Replay(t Target, realtime bool) (err error) {
+	var last time.Duration
+	for first := true; ; first = false {
+		var e wde.Event
+		e, err = rd.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return
+		}
+		if realtime && !first && e.Timestamp() > last {
+			time.Sleep(e.Timestamp() - last)
+		}
+		last = e.Timestamp()
+		if err = t.Inject(e); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,255 @@
+/*
+   Copyright 2012 the go.wde authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package record
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/skelterjohn/go.wde"
+	"github.com/skelterjohn/go.wde/headless"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// A replayed ResizeEvent resizes the window, and reaches the application
+// once, as recorded, even when the window is already that size.
+func TestReplayResizes(t *testing.T) {
+	var buf bytes.Buffer
+	rec, err := NewRecorder(&buf, 10, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var re, same wde.ResizeEvent
+	re.When = time.Second
+	re.Width, re.Height = 30, 20
+	same.Width, same.Height = 30, 20
+	var ke wde.KeyDownEvent
+	ke.Key = wde.KeyA
+	for _, e := range []wde.Event{wde.FocusEvent{Gained: true}, re, same, ke} {
+		if err := rec.Record(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rd, err := NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := headless.NewWindow(rd.Header.Width, rd.Header.Height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if err := rd.Replay(w, false); err != nil {
+		t.Fatal(err)
+	}
+	if width, height := w.Size(); width != 30 || height != 20 {
+		t.Errorf("window is %dx%d after replay, want 30x20", width, height)
+	}
+
+	var got []wde.Event
+	for len(got) < 4 {
+		select {
+		case e := <-w.EventChan():
+			got = append(got, e)
+		case <-time.After(time.Second):
+			t.Fatalf("got %d events, want 4", len(got))
+		}
+	}
+	if _, ok := got[0].(wde.FocusEvent); !ok {
+		t.Errorf("event 0 is %T, want wde.FocusEvent", got[0])
+	}
+	for i := 1; i <= 2; i++ {
+		if e, ok := got[i].(wde.ResizeEvent); !ok || e.Width != 30 || e.Height != 20 {
+			t.Errorf("event %d is %#v, want a 30x20 wde.ResizeEvent", i, got[i])
+		}
+	}
+	if e := got[1]; e.Timestamp() != time.Second {
+		t.Errorf("event 1 is at %v, want the recorded %v", e.Timestamp(), time.Second)
+	}
+	if _, ok := got[3].(wde.KeyDownEvent); !ok {
+		t.Errorf("event 3 is %T, want wde.KeyDownEvent", got[3])
+	}
+	select {
+	case e := <-w.EventChan():
+		t.Errorf("unexpected %T after the replayed events", e)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+// fill sets every field of v that a recording holds to a value of its own,
+// counting from *n.
+func fill(v reflect.Value, n *int) {
+	*n++
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if f := v.Type().Field(i); f.PkgPath == "" || f.Anonymous {
+				fill(v.Field(i), n)
+			}
+		}
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 2, 2))
+		fill(v.Index(0), n)
+		fill(v.Index(1), n)
+	case reflect.Int, reflect.Int64:
+		v.SetInt(int64(*n))
+	case reflect.Uint, reflect.Uint16:
+		v.SetUint(uint64(*n))
+	case reflect.Float64:
+		v.SetFloat(float64(*n) + 0.5)
+	case reflect.String:
+		v.SetString(fmt.Sprintf("s%d", *n))
+	case reflect.Bool:
+		v.SetBool(true)
+	}
+}
+
+// Every kind of event comes back from a recording as it went in, timestamp
+// and all.
+func TestRoundTrip(t *testing.T) {
+	var names []string
+	for name := range eventTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var events []wde.Event
+	n := 0
+	for _, name := range names {
+		v := reflect.New(eventTypes[name]).Elem()
+		fill(v, &n)
+		events = append(events, v.Interface().(wde.Event))
+	}
+
+	var buf bytes.Buffer
+	rec, err := NewRecorder(&buf, 640, 480)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range events {
+		if err := rec.Record(e); err != nil {
+			t.Fatalf("recording %T: %v", e, err)
+		}
+	}
+
+	rd, err := NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h := rd.Header; h.Format != Format || h.Version != Version || h.Width != 640 || h.Height != 480 {
+		t.Errorf("header is %+v", h)
+	}
+	for _, want := range events {
+		got, err := rd.Next()
+		if err != nil {
+			t.Fatalf("reading %T: %v", want, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("recorded %#v, read %#v", want, got)
+		}
+	}
+	if _, err := rd.Next(); err != io.EOF {
+		t.Errorf("got %v after the last event, want io.EOF", err)
+	}
+}
+
+type otherEvent struct {
+	wde.FocusEvent
+}
+
+func TestRecordUnknown(t *testing.T) {
+	rec, err := NewRecorder(io.Discard, 10, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range []wde.Event{nil, &wde.FocusEvent{}, otherEvent{}} {
+		if err := rec.Record(e); err == nil {
+			t.Errorf("recorded a %T", e)
+		}
+	}
+	if err := rec.Err(); err != nil {
+		t.Errorf("rejected events stopped the recording: %v", err)
+	}
+}
+
+func TestNewReader(t *testing.T) {
+	for _, test := range []struct {
+		in string
+		ok bool
+	}{
+		{`{"Format":"wde-events","Version":1,"Width":4,"Height":3}`, true},
+		{`{"Format":"wde-events","Version":0,"Width":4,"Height":3}`, true},
+		{`{"Format":"wde-events","Version":2,"Width":4,"Height":3}`, false},
+		{`{"Format":"other","Version":1}`, false},
+		{`{"Type":"FocusEvent","Event":{}}`, false},
+		{`not json`, false},
+		{``, false},
+	} {
+		rd, err := NewReader(strings.NewReader(test.in))
+		switch {
+		case test.ok && err != nil:
+			t.Errorf("NewReader(%s): %v", test.in, err)
+		case !test.ok && err == nil:
+			t.Errorf("NewReader(%s) read header %+v", test.in, rd.Header)
+		}
+	}
+	// a newer version is a recording all the same, just not one we can read
+	_, err := NewReader(strings.NewReader(`{"Format":"wde-events","Version":2}`))
+	if err == nil || err == ErrFormat {
+		t.Errorf("got %v for a newer version, want an error other than ErrFormat", err)
+	}
+	_, err = NewReader(strings.NewReader(`not json`))
+	if err != ErrFormat {
+		t.Errorf("got %v for a non-recording, want ErrFormat", err)
+	}
+}
+
+// In real time, replay takes as long as the recorded events' timestamps span.
+func TestReplayRealtime(t *testing.T) {
+	var buf bytes.Buffer
+	rec, err := NewRecorder(&buf, 10, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const gap = 20 * time.Millisecond
+	for i := 0; i < 4; i++ {
+		var fe wde.FocusEvent
+		fe.When = time.Hour + time.Duration(i)*gap
+		rec.Record(fe)
+	}
+
+	rd, err := NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := headless.NewWindow(10, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	start := time.Now()
+	if err := rd.Replay(w, true); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d < 3*gap {
+		t.Errorf("replay took %v, want at least %v", d, 3*gap)
+	}
+}
@@ -0,0 +1,151 @@
+/*
+   Copyright 2012 the go.wde authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package wde
+
+/*
+A Scancode names a physical key by where it is on the keyboard, whatever
+the layout says it types. The values are the usages of the USB HID keyboard
+page, and the constants are named after the keys in those places on a US
+keyboard: on an AZERTY keyboard, ScancodeQ is the key labelled A. Keys a
+backend cannot place have a Scancode of 0.
+*/
+type Scancode uint16
+
+const (
+	ScancodeA              Scancode = 0x04
+	ScancodeB              Scancode = 0x05
+	ScancodeC              Scancode = 0x06
+	ScancodeD              Scancode = 0x07
+	ScancodeE              Scancode = 0x08
+	ScancodeF              Scancode = 0x09
+	ScancodeG              Scancode = 0x0a
+	ScancodeH              Scancode = 0x0b
+	ScancodeI              Scancode = 0x0c
+	ScancodeJ              Scancode = 0x0d
+	ScancodeK              Scancode = 0x0e
+	ScancodeL              Scancode = 0x0f
+	ScancodeM              Scancode = 0x10
+	ScancodeN              Scancode = 0x11
+	ScancodeO              Scancode = 0x12
+	ScancodeP              Scancode = 0x13
+	ScancodeQ              Scancode = 0x14
+	ScancodeR              Scancode = 0x15
+	ScancodeS              Scancode = 0x16
+	ScancodeT              Scancode = 0x17
+	ScancodeU              Scancode = 0x18
+	ScancodeV              Scancode = 0x19
+	ScancodeW              Scancode = 0x1a
+	ScancodeX              Scancode = 0x1b
+	ScancodeY              Scancode = 0x1c
+	ScancodeZ              Scancode = 0x1d
+	Scancode1              Scancode = 0x1e
+	Scancode2              Scancode = 0x1f
+	Scancode3              Scancode = 0x20
+	Scancode4              Scancode = 0x21
+	Scancode5              Scancode = 0x22
+	Scancode6              Scancode = 0x23
+	Scancode7              Scancode = 0x24
+	Scancode8              Scancode = 0x25
+	Scancode9              Scancode = 0x26
+	Scancode0              Scancode = 0x27
+	ScancodeReturn         Scancode = 0x28
+	ScancodeEscape         Scancode = 0x29
+	ScancodeBackspace      Scancode = 0x2a
+	ScancodeTab            Scancode = 0x2b
+	ScancodeSpace          Scancode = 0x2c
+	ScancodeMinus          Scancode = 0x2d
+	ScancodeEqual          Scancode = 0x2e
+	ScancodeLeftBracket    Scancode = 0x2f
+	ScancodeRightBracket   Scancode = 0x30
+	ScancodeBackslash      Scancode = 0x31
+	ScancodeNonUSHash      Scancode = 0x32
+	ScancodeSemicolon      Scancode = 0x33
+	ScancodeQuote          Scancode = 0x34
+	ScancodeBackTick       Scancode = 0x35
+	ScancodeComma          Scancode = 0x36
+	ScancodePeriod         Scancode = 0x37
+	ScancodeSlash          Scancode = 0x38
+	ScancodeCapsLock       Scancode = 0x39
+	ScancodeF1             Scancode = 0x3a
+	ScancodeF2             Scancode = 0x3b
+	ScancodeF3             Scancode = 0x3c
+	ScancodeF4             Scancode = 0x3d
+	ScancodeF5             Scancode = 0x3e
+	ScancodeF6             Scancode = 0x3f
+	ScancodeF7             Scancode = 0x40
+	ScancodeF8             Scancode = 0x41
+	ScancodeF9             Scancode = 0x42
+	ScancodeF10            Scancode = 0x43
+	ScancodeF11            Scancode = 0x44
+	ScancodeF12            Scancode = 0x45
+	ScancodePrintScreen    Scancode = 0x46
+	ScancodeScrollLock     Scancode = 0x47
+	ScancodePause          Scancode = 0x48
+	ScancodeInsert         Scancode = 0x49
+	ScancodeHome           Scancode = 0x4a
+	ScancodePrior          Scancode = 0x4b
+	ScancodeDelete         Scancode = 0x4c
+	ScancodeEnd            Scancode = 0x4d
+	ScancodeNext           Scancode = 0x4e
+	ScancodeRightArrow     Scancode = 0x4f
+	ScancodeLeftArrow      Scancode = 0x50
+	ScancodeDownArrow      Scancode = 0x51
+	ScancodeUpArrow        Scancode = 0x52
+	ScancodeNumlock        Scancode = 0x53
+	ScancodePadSlash       Scancode = 0x54
+	ScancodePadStar        Scancode = 0x55
+	ScancodePadMinus       Scancode = 0x56
+	ScancodePadPlus        Scancode = 0x57
+	ScancodePadEnter       Scancode = 0x58
+	ScancodePad1           Scancode = 0x59
+	ScancodePad2           Scancode = 0x5a
+	ScancodePad3           Scancode = 0x5b
+	ScancodePad4           Scancode = 0x5c
+	ScancodePad5           Scancode = 0x5d
+	ScancodePad6           Scancode = 0x5e
+	ScancodePad7           Scancode = 0x5f
+	ScancodePad8           Scancode = 0x60
+	ScancodePad9           Scancode = 0x61
+	ScancodePad0           Scancode = 0x62
+	ScancodePadDot         Scancode = 0x63
+	ScancodeNonUSBackslash Scancode = 0x64
+	ScancodeMenu           Scancode = 0x65
+	ScancodePadEqual       Scancode = 0x67
+	ScancodeF13            Scancode = 0x68
+	ScancodeF14            Scancode = 0x69
+	ScancodeF15            Scancode = 0x6a
+	ScancodeF16            Scancode = 0x6b
+	ScancodeF17            Scancode = 0x6c
+	ScancodeF18            Scancode = 0x6d
+	ScancodeF19            Scancode = 0x6e
+	ScancodeF20            Scancode = 0x6f
+	ScancodeF21            Scancode = 0x70
+	ScancodeF22            Scancode = 0x71
+	ScancodeF23            Scancode = 0x72
+	ScancodeF24            Scancode = 0x73
+	ScancodeMute           Scancode = 0x7f
+	ScancodeVolumeUp       Scancode = 0x80
+	ScancodeVolumeDown     Scancode = 0x81
+	ScancodeLeftControl    Scancode = 0xe0
+	ScancodeLeftShift      Scancode = 0xe1
+	ScancodeLeftAlt        Scancode = 0xe2
+	ScancodeLeftSuper      Scancode = 0xe3
+	ScancodeRightControl   Scancode = 0xe4
+	ScancodeRightShift     Scancode = 0xe5
+	ScancodeRightAlt       Scancode = 0xe6
+	ScancodeRightSuper     Scancode = 0xe7
+)
@@ -0,0 +1,89 @@
+/*
+   Copyright 2012 the go.wde authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package wde
+
+import (
+	"errors"
+	"image"
+)
+
+var ErrUnsupported = errors.New("wde: not supported by this backend")
+
+// A Screen is one monitor attached to the display.
+type Screen struct {
+	Name string
+	// Bounds is where the screen is, in the coordinates Window.Position
+	// uses. The screens together may not fill a rectangle.
+	Bounds image.Rectangle
+	// WorkArea is the part of Bounds left for windows once panels, docks
+	// and task bars have taken their share.
+	WorkArea image.Rectangle
+	// DPI is the screen's resolution in pixels per inch, or 0 if unknown.
+	DPI float64
+	// RefreshRate is how often the screen refreshes, in Hz, or 0 if
+	// unknown.
+	RefreshRate float64
+	// Scale is the scale factor of windows on the screen, as reported by
+	// Window.Scale.
+	Scale float64
+	// Primary is set for the screen the user chose as the main one.
+	Primary bool
+}
+
+/*
+Screens lists the monitors attached to the display, the primary one first
+where the backend knows which it is. Backends that cannot tell them apart
+report a single screen covering the whole display.
+*/
+func Screens() ([]Screen, error) {
+	return BackendScreens()
+}
+
+var BackendScreens = func() ([]Screen, error) {
+	return nil, ErrUnsupported
+}
+
+/*
+ScreenAt returns the screen among screens that holds p, or failing that the
+one nearest to it. It reports false only if screens is empty. A window is
+on the screen that holds its middle.
+*/
+func ScreenAt(screens []Screen, p image.Point) (s Screen, ok bool) {
+	best := -1
+	for _, sc := range screens {
+		if p.In(sc.Bounds) {
+			return sc, true
+		}
+		// the distance to the nearest edge, along each axis
+		d := image.Pt(0, 0)
+		if p.X < sc.Bounds.Min.X {
+			d.X = sc.Bounds.Min.X - p.X
+		} else if p.X >= sc.Bounds.Max.X {
+			d.X = p.X - sc.Bounds.Max.X + 1
+		}
+		if p.Y < sc.Bounds.Min.Y {
+			d.Y = sc.Bounds.Min.Y - p.Y
+		} else if p.Y >= sc.Bounds.Max.Y {
+			d.Y = p.Y - sc.Bounds.Max.Y + 1
+		}
+		if dist := d.X*d.X + d.Y*d.Y; best < 0 || dist < best {
+			best = dist
+			s, ok = sc, true
+		}
+	}
+	return
+}
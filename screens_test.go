@@ -0,0 +1,59 @@
+/*
+   Copyright 2012 the go.wde authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package wde
+
+import (
+	"image"
+	"testing"
+)
+
+func TestScreenAt(t *testing.T) {
+	// a laptop with a smaller monitor to its right, their tops aligned, and
+	// the laptop's display mirrored on a projector
+	screens := []Screen{
+		{Name: "laptop", Bounds: image.Rect(0, 0, 1920, 1080), Primary: true},
+		{Name: "monitor", Bounds: image.Rect(1920, 0, 3200, 1024)},
+		{Name: "projector", Bounds: image.Rect(0, 0, 1920, 1080)},
+	}
+	for _, test := range []struct {
+		p    image.Point
+		want string
+	}{
+		{image.Pt(0, 0), "laptop"},
+		{image.Pt(960, 540), "laptop"},
+		// the edge the screens share belongs to the one it begins
+		{image.Pt(1919, 500), "laptop"},
+		{image.Pt(1920, 500), "monitor"},
+		{image.Pt(3199, 1023), "monitor"},
+		// outside every screen, the nearest holds it
+		{image.Pt(-10, -10), "laptop"},
+		{image.Pt(960, 5000), "laptop"},
+		{image.Pt(3300, 500), "monitor"},
+		{image.Pt(2500, 1050), "monitor"},
+		{image.Pt(1950, 1070), "laptop"},
+		{image.Pt(3000, 1100), "monitor"},
+	} {
+		s, ok := ScreenAt(screens, test.p)
+		if !ok || s.Name != test.want {
+			t.Errorf("ScreenAt(%v) = %q, %v; want %q", test.p, s.Name, ok, test.want)
+		}
+	}
+
+	if s, ok := ScreenAt(nil, image.Pt(0, 0)); ok || s != (Screen{}) {
+		t.Errorf("ScreenAt of no screens = %+v, %v; want none", s, ok)
+	}
+}
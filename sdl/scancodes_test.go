@@ -0,0 +1,38 @@
+package sdlw
+
+import (
+	"github.com/jackyb/go-sdl2/sdl"
+	"github.com/skelterjohn/go.wde"
+	"testing"
+)
+
+func TestScancodeForSDL(t *testing.T) {
+	for _, test := range []struct {
+		code sdl.Scancode
+		want wde.Scancode
+	}{
+		{4, wde.ScancodeA},                // SDL_SCANCODE_A
+		{29, wde.ScancodeZ},               // SDL_SCANCODE_Z
+		{30, wde.Scancode1},               // SDL_SCANCODE_1
+		{39, wde.Scancode0},               // SDL_SCANCODE_0
+		{40, wde.ScancodeReturn},          // SDL_SCANCODE_RETURN
+		{41, wde.ScancodeEscape},          // SDL_SCANCODE_ESCAPE
+		{53, wde.ScancodeBackTick},        // SDL_SCANCODE_GRAVE
+		{58, wde.ScancodeF1},              // SDL_SCANCODE_F1
+		{69, wde.ScancodeF12},             // SDL_SCANCODE_F12
+		{72, wde.ScancodePause},           // SDL_SCANCODE_PAUSE
+		{83, wde.ScancodeNumlock},         // SDL_SCANCODE_NUMLOCKCLEAR
+		{88, wde.ScancodePadEnter},        // SDL_SCANCODE_KP_ENTER
+		{98, wde.ScancodePad0},            // SDL_SCANCODE_KP_0
+		{100, wde.ScancodeNonUSBackslash}, // SDL_SCANCODE_NONUSBACKSLASH
+		{101, wde.ScancodeMenu},           // SDL_SCANCODE_APPLICATION
+		{224, wde.ScancodeLeftControl},    // SDL_SCANCODE_LCTRL
+		{231, wde.ScancodeRightSuper},     // SDL_SCANCODE_RGUI
+		{0, 0},                            // SDL_SCANCODE_UNKNOWN
+		{257, 0},                          // SDL_SCANCODE_MODE
+	} {
+		if got := scancodeForSDL(test.code); got != test.want {
+			t.Errorf("scancodeForSDL(%d) = %#x, want %#x", test.code, got, test.want)
+		}
+	}
+}
@@ -62,7 +62,7 @@ var keys = []string {
 	wde.KeyComma,
 	wde.KeyPeriod,
 	wde.KeySlash,
-	"",
+	wde.KeyCapsLock,
 	wde.KeyF1,
 	wde.KeyF2,
 	wde.KeyF3,
@@ -75,21 +75,71 @@ var keys = []string {
 	wde.KeyF10,
 	wde.KeyF11,
 	wde.KeyF12,
-	"", //70
-	"",
-	"",
+	wde.KeyPrintScreen, //70
+	wde.KeyScrollLock,
+	wde.KeyPause,
 	wde.KeyInsert,
-	"",
-	"",
+	wde.KeyHome,
+	wde.KeyPrior,
 	wde.KeyDelete,
+	wde.KeyEnd,
+	wde.KeyNext,
+	wde.KeyRightArrow,
+	wde.KeyLeftArrow, //80
+	wde.KeyDownArrow,
+	wde.KeyUpArrow,
+	wde.KeyNumlock,
+	wde.KeyPadSlash,
+	wde.KeyPadStar,
+	wde.KeyPadMinus,
+	wde.KeyPadPlus,
+	wde.KeyPadEnter,
+	wde.KeyPad1,
+	wde.KeyPad2, //90
+	wde.KeyPad3,
+	wde.KeyPad4,
+	wde.KeyPad5,
+	wde.KeyPad6,
+	wde.KeyPad7,
+	wde.KeyPad8,
+	wde.KeyPad9,
+	wde.KeyPad0,
+	wde.KeyPadDot,
+	wde.KeyIntlBackslash, //100
+	wde.KeyMenu,
+	"",
+	wde.KeyPadEqual,
+	wde.KeyF13,
+	wde.KeyF14,
+	wde.KeyF15,
+	wde.KeyF16,
+	wde.KeyF17,
+	wde.KeyF18,
+	wde.KeyF19, //110
+	wde.KeyF20,
+	wde.KeyF21,
+	wde.KeyF22,
+	wde.KeyF23,
+	wde.KeyF24,
 }
 
 func init() {
-	keys = append(keys, make([]string, 256 - len(keys))...)
+	keys = append(keys, make([]string, 263 - len(keys))...)
+	keys[127] = wde.KeyMute
+	keys[128] = wde.KeyVolumeUp
+	keys[129] = wde.KeyVolumeDown
+	keys[133] = wde.KeyPadComma
 	keys[224] = wde.KeyLeftControl
 	keys[225] = wde.KeyLeftShift
 	keys[226] = wde.KeyLeftAlt
+	keys[227] = wde.KeyLeftSuper
+	keys[228] = wde.KeyRightControl
 	keys[229] = wde.KeyRightShift
 	keys[230] = wde.KeyRightAlt
-
+	keys[231] = wde.KeyRightSuper
+	keys[258] = wde.KeyMediaNext
+	keys[259] = wde.KeyMediaPrev
+	keys[260] = wde.KeyMediaStop
+	keys[261] = wde.KeyMediaPlay
+	keys[262] = wde.KeyMute
 }
@@ -0,0 +1,14 @@
+package sdlw
+
+import (
+	"github.com/skelterjohn/go.wde"
+	"testing"
+)
+
+func TestKeysKnown(t *testing.T) {
+	for code, key := range keys {
+		if key != "" && !wde.KnownKey(key) {
+			t.Errorf("keys[%d] = %q, which is not a wde key", code, key)
+		}
+	}
+}
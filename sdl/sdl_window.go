@@ -7,6 +7,9 @@ import (
 	"github.com/jackyb/go-sdl2/sdl"
 	"runtime"
 	"log"
+	"sync"
+	"time"
+	"unsafe"
 )
 
 var windowList []*Window
@@ -14,27 +17,33 @@ var newWindow chan *Window
 var windowShow chan *Window
 var windowFlush chan *Window
 var windowChSize chan *Window
+var windowMove chan *Window
+var windowFullscreen chan *Window
+var windowState chan *Window
 var windowTitle chan *Window
+var windowClose chan *Window
+var screensCh chan chan []wde.Screen
 var active *Window
-var keychords map[string]bool
-
-var events chan interface{}
+var clock wde.MillisecondClock
 
 func init() {
 	fmt.Println("Initializing!")
 	wde.BackendNewWindow = NewWindow
+	wde.BackendNewWindowWithOptions = NewWindowWithOptions
 	e := sdl.Init(sdl.INIT_EVERYTHING)
 	fmt.Printf("SDL_Init returned: %d\n", e)
 
-	keychords	= make(map[string]bool)
-
 	newWindow = make(chan *Window)
 	windowShow = make(chan *Window)
 	windowFlush = make(chan *Window)
 	windowChSize = make(chan *Window)
+	windowMove = make(chan *Window)
+	windowFullscreen = make(chan *Window)
+	windowState = make(chan *Window)
 	windowTitle = make(chan *Window)
-
-	events = make(chan interface{}, 32)
+	windowClose = make(chan *Window)
+	screensCh = make(chan chan []wde.Screen)
+	wde.BackendScreens = Screens
 
 	ch := make(chan struct{}, 1)
 	wde.BackendRun = func() {
@@ -51,6 +60,9 @@ type Window struct {
 	w *sdl.Window
 	r *sdl.Renderer
 	buffer *SdlBuffer
+	// tex holds the last flushed frame, and is as big as buffer
+	tex *sdl.Texture
+	texSize image.Point
 	lock bool
 
 	closed bool
@@ -58,26 +70,76 @@ type Window struct {
 	Id int
 
 	title string
+	// opts are what the window was created with
+	opts wde.WindowOptions
 
 	opdone chan struct{}
+	// created reports whether SDL could make the window
+	created chan error
 
 	width, height int
+	// x and y are where SetPosition last asked for the window to go
+	x, y int
+	scale float64
+	// fullscreen and wantState are what SetFullscreen and the other state
+	// methods last asked for; state is what the window was last reported to be
+	fullscreen bool
+	wantState wde.WindowState
+	state wde.WindowState
 	keychords map[string]bool
+	clicks wde.ClickCounter
+	events chan wde.Event
+	// queue holds the events the SDL thread has translated until pump sends
+	// them, so that the SDL thread never waits for the application, which
+	// may itself be waiting for the SDL thread. wake tells pump there is
+	// more, and done that the window has closed.
+	queueLock sync.Mutex
+	queue []wde.Event
+	wake chan struct{}
+	done chan struct{}
+
+	// last is where the pointer was last seen in the window, if seenPointer
+	last image.Point
+	seenPointer bool
+
+	// flushRects are the parts of the screen the pending FlushImage covers
+	flushRects []image.Rectangle
 }
 
 type point image.Point
 
 func NewWindow(width, height int)  (wde.Window, error) {
-	fmt.Printf("new window, width %d height %d\n", width, height)
+	return NewWindowWithOptions(wde.WindowOptions{Width: width, Height: height})
+}
+
+/*
+NewWindowWithOptions creates a window as opts describe. SDL windows cannot
+be resized by the user, so MinSize, MaxSize and FixedSize make no
+difference, and SDL has no way to ask for AlwaysOnTop or Parent, which are
+ignored.
+*/
+func NewWindowWithOptions(opts wde.WindowOptions) (wde.Window, error) {
+	width, height := opts.Width, opts.Height
 	w := new(Window)
 	w.width = width
 	w.height = height
+	w.title = opts.Title
+	w.opts = opts
 
 	w.buffer = NewSdlBuffer(width, height)
 
 	w.opdone = make(chan struct{})
+	w.created = make(chan error)
+	w.keychords = make(map[string]bool)
+	w.events = make(chan wde.Event, 32)
+	w.wake = make(chan struct{}, 1)
+	w.done = make(chan struct{})
+	go w.pump()
 	newWindow<-w
-	<-w.opdone
+	if err := <-w.created; err != nil {
+		close(w.done)
+		return nil, err
+	}
 	return w, nil
 }
 
@@ -111,17 +173,77 @@ func (w *Window) Size() (width, height int) {
 	return w.w.GetSize()
 }
 
+// Position reports where the window is. SDL places windows by their
+// content, so the frame is not included.
+func (w *Window) Position() (x, y int) {
+	if w.closed {
+		return
+	}
+	return w.w.GetPosition()
+}
+
+func (w *Window) SetPosition(x, y int) {
+	if w.closed {
+		return
+	}
+	w.x, w.y = x, y
+	windowMove <- w
+	<-w.opdone
+}
+
+func (w *Window) Center() {
+	w.SetPosition(sdl.WINDOWPOS_CENTERED, sdl.WINDOWPOS_CENTERED)
+}
+
+func (w *Window) Scale() float64 {
+	return w.scale
+}
+
+func (w *Window) SetFullscreen(fullscreen bool) {
+	if w.closed {
+		return
+	}
+	w.fullscreen = fullscreen
+	windowFullscreen <- w
+	<-w.opdone
+}
+
+func (w *Window) Maximize() {
+	w.changeState(wde.StateMaximized)
+}
+
+func (w *Window) Minimize() {
+	w.changeState(wde.StateMinimized)
+}
+
+func (w *Window) Restore() {
+	w.changeState(wde.StateNormal)
+}
+
+func (w *Window) changeState(state wde.WindowState) {
+	if w.closed {
+		return
+	}
+	w.wantState = state
+	windowState <- w
+	<-w.opdone
+}
+
 func (w *Window) LockSize(lock bool) {
 	w.lock = lock
 }
 
-func (w *Window) EventChan() <-chan interface{} {
-	return events
+func (w *Window) EventChan() <-chan wde.Event {
+	return w.events
 }
 
 func (w *Window) Close() error {
-	w.w.Destroy()
+	if w.closed {
+		return nil
+	}
 	w.closed = true
+	windowClose <- w
+	<-w.opdone
 	return nil
 }
 
@@ -129,6 +251,7 @@ func (w *Window) FlushImage(r ...image.Rectangle) {
 	if w.closed {
 		return
 	}
+	w.flushRects = wde.FlushRects(image.Rect(0, 0, w.width, w.height), r)
 	windowFlush <- w
 	<-w.opdone
 }
@@ -152,20 +275,15 @@ func sdlWindowLoop() {
 	for {
 		select {
 		case w := <-newWindow:
+			if err := w.setupWindow(); err != nil {
+				w.created <- err
+				break
+			}
 			w.Id = len(windowList)
 			windowList = append(windowList, w)
-			w.setupWindow()
-			w.opdone<-struct{}{}
+			w.created <- nil
 		case w := <-windowFlush:
-			for x := 0; x < w.width; x++ {
-				for y := 0; y < w.height; y++ {
-					r,g,b,a := w.buffer.At(x,y).RGBA()
-					w.r.SetDrawColor(uint8(r),uint8(g),uint8(b),uint8(a))
-					w.r.DrawPoint(x,y)
-				}
-			}
-			w.r.Present()
-			w.buffer.Clear()
+			w.flush()
 			w.opdone<-struct{}{}
 		case w := <-windowShow:
 			w.w.Show()
@@ -175,9 +293,30 @@ func sdlWindowLoop() {
 				w.w.SetSize(w.width, w.height)
 			}
 			w.opdone<-struct{}{}
+		case w := <-windowMove:
+			w.w.SetPosition(w.x, w.y)
+			w.opdone <- struct{}{}
+		case w := <-windowFullscreen:
+			w.applyFullscreen()
+			w.opdone <- struct{}{}
+		case w := <-windowState:
+			w.applyState()
+			w.opdone <- struct{}{}
 		case w := <-windowTitle:
 			w.w.SetTitle(w.title)
 			w.opdone <- struct{}{}
+		case ch := <-screensCh:
+			ch <- listScreens()
+		case w := <-windowClose:
+			for i, lw := range windowList {
+				if lw == w {
+					windowList = append(windowList[:i], windowList[i+1:]...)
+					break
+				}
+			}
+			w.w.Destroy()
+			close(w.done)
+			w.opdone <- struct{}{}
 		default:
 			for collectEvents() {}
 			time.Sleep(time.Millisecond * 10);
@@ -193,75 +332,206 @@ func collectEvents() bool {
 	//Event translation
 	switch e := e.(type) {
 	case *sdl.KeyDownEvent:
-		rev := new(wde.KeyDownEvent)
+		w := windowForID(e.WindowID)
+		if w == nil {
+			return true
+		}
+		var rev wde.KeyDownEvent
+		rev.Source = w
+		rev.When = clock.Stamp(e.Timestamp)
+		rev.Mods = modifiersForKeymod(uint16(e.Keysym.Mod))
 		rev.Key = ConvertKeyCode(e.Keysym.Scancode)
-		keychords[rev.Key] = true
-		events <- rev
-		chord := new(wde.KeyTypedEvent)
-		chord.Chord = wde.ConstructChord(keychords)
-		events <- chord
+		rev.Scancode = scancodeForSDL(e.Keysym.Scancode)
+		rev.Repeat = e.Repeat != 0
+		w.keychords[rev.Key] = true
+		w.send(rev)
+		var chord wde.KeyTypedEvent
+		chord.KeyEvent = rev.KeyEvent
+		chord.Repeat = rev.Repeat
+		chord.Chord = wde.ConstructChord(w.keychords)
+		w.send(chord)
 		return true
 	case *sdl.KeyUpEvent:
-		rev := new(wde.KeyUpEvent)
+		w := windowForID(e.WindowID)
+		if w == nil {
+			return true
+		}
+		var rev wde.KeyUpEvent
+		rev.Source = w
+		rev.When = clock.Stamp(e.Timestamp)
+		rev.Mods = modifiersForKeymod(uint16(e.Keysym.Mod))
 		rev.Key = ConvertKeyCode(e.Keysym.Scancode)
-		keychords[rev.Key] = false
-		events <- rev
-		chord := new(wde.KeyTypedEvent)
-		chord.Chord = wde.ConstructChord(keychords)
-		events <- chord
+		rev.Scancode = scancodeForSDL(e.Keysym.Scancode)
+		delete(w.keychords, rev.Key)
+		w.send(rev)
+		return true
+	case *sdl.TextInputEvent:
+		w := windowForID(e.WindowID)
+		if w == nil {
+			return true
+		}
+		var te wde.TextInputEvent
+		te.Source = w
+		te.When = clock.Stamp(e.Timestamp)
+		te.Mods = modifiersForKeymod(uint16(sdl.GetModState()))
+		te.Text = cString(e.Text[:])
+		w.send(te)
+		return true
+	case *sdl.TextEditingEvent:
+		w := windowForID(e.WindowID)
+		if w == nil {
+			return true
+		}
+		var ce wde.TextCompositionEvent
+		ce.Source = w
+		ce.When = clock.Stamp(e.Timestamp)
+		ce.Mods = modifiersForKeymod(uint16(sdl.GetModState()))
+		ce.Text = cString(e.Text[:])
+		ce.Cursor = int(e.Start)
+		w.send(ce)
 		return true
 	case *sdl.MouseButtonEvent:
-		fmt.Println("Mouse button event...")
-		rev := new(wde.MouseButtonEvent)
-		rev.Which = wde.Button(1 << e.Button)
-		log.Printf("Button: %d\n",e.Button)
-		rev.Where = image.Pt(int(e.X),int(e.Y))
-		events <- rev
+		w := windowForID(e.WindowID)
+		if w == nil {
+			return true
+		}
+		var rev wde.MouseButtonEvent
+		rev.Source = w
+		rev.When = clock.Stamp(e.Timestamp)
+		rev.Mods = modifiersForKeymod(uint16(sdl.GetModState()))
+		rev.Which = buttonForSDL(e.Button)
+		rev.Where = image.Pt(int(e.X), int(e.Y))
+		w.moved(rev.Where)
+		if e.State == sdl.PRESSED {
+			rev.Clicks = w.clicks.Press(rev.Which, rev.Where, rev.When)
+			w.send(wde.MouseDownEvent(rev))
+		} else {
+			rev.Clicks = w.clicks.Release(rev.Which)
+			w.send(wde.MouseUpEvent(rev))
+		}
 		return true
 	case *sdl.MouseMotionEvent:
-
+		w := windowForID(e.WindowID)
+		if w == nil {
+			return true
+		}
+		var mme wde.MouseMovedEvent
+		mme.Source = w
+		mme.When = clock.Stamp(e.Timestamp)
+		mme.Mods = modifiersForKeymod(uint16(sdl.GetModState()))
+		mme.Where = image.Pt(int(e.X), int(e.Y))
+		mme.From = w.moved(mme.Where)
+		if held := buttonsForState(e.State); held == 0 {
+			w.send(mme)
+		} else {
+			var mde wde.MouseDraggedEvent
+			mde.MouseMovedEvent = mme
+			mde.Which = held
+			w.send(mde)
+		}
 		return true
 	case *sdl.MouseWheelEvent:
+		w := windowForID(e.WindowID)
+		if w == nil {
+			return true
+		}
+		var se wde.ScrollEvent
+		se.Source = w
+		se.When = clock.Stamp(e.Timestamp)
+		se.Mods = modifiersForKeymod(uint16(sdl.GetModState()))
+		// wheel events do not say where the pointer is
+		x, y, _ := sdl.GetMouseState()
+		se.Where = image.Pt(int(x), int(y))
+		se.DeltaX = float64(e.X)
+		se.DeltaY = float64(e.Y)
+		w.send(se)
 		return true
 	case *sdl.QuitEvent:
-		events <- new(wde.CloseEvent)
+		// every window has already had its own WINDOWEVENT_CLOSE
 		return true
 	case *sdl.WindowEvent:
+		w := windowForID(e.WindowID)
+		if w == nil {
+			return true
+		}
+		when := clock.Stamp(e.Timestamp)
 		switch e.Event {
 			//http://wiki.libsdl.org/moin.fcg/SDL_WindowEvent
 		case sdl.WINDOWEVENT_SHOWN:
 			log.Println("Window shown!")
 		case sdl.WINDOWEVENT_RESTORED:
-			log.Println("Window restored.")
+			// a minimized fullscreen window is restored to fullscreen
+			if w.w.GetFlags()&sdl.WINDOW_FULLSCREEN_DESKTOP == sdl.WINDOW_FULLSCREEN_DESKTOP {
+				w.setState(wde.StateFullscreen, when)
+			} else {
+				w.setState(wde.StateNormal, when)
+			}
 		case sdl.WINDOWEVENT_EXPOSED:
-			log.Println("Window exposed, whatever that means...")
+			w.present()
+			var ee wde.ExposeEvent
+			ee.Source = w
+			ee.When = when
+			ee.Rects = []image.Rectangle{image.Rect(0, 0, w.width, w.height)}
+			w.send(ee)
 		case sdl.WINDOWEVENT_HIDDEN:
 			log.Println("Window hidden.. sneaky thing.")
 		case sdl.WINDOWEVENT_MAXIMIZED:
-			log.Println("Window Maximized!")
+			w.setState(wde.StateMaximized, when)
 		case sdl.WINDOWEVENT_MINIMIZED:
-			log.Println("Window Minimized!")
-		case sdl.WINDOWEVENT_ENTER:
-			events <- new(wde.MouseEnteredEvent)
-			log.Println("Mouse enter...")
-		case sdl.WINDOWEVENT_LEAVE:
-			events <- new(wde.MouseExitedEvent)
-			log.Println("Mouse leave...")
-		case sdl.WINDOWEVENT_RESIZED:
-			log.Printf("resize to: %d %d\n", e.Data1, e.Data2)
-			rev := new(wde.ResizeEvent)
-			rev.Height = int(e.Data1)
-			rev.Width = int(e.Data2)
-			events <- rev
+			w.setState(wde.StateMinimized, when)
+		case sdl.WINDOWEVENT_ENTER, sdl.WINDOWEVENT_LEAVE:
+			// window events do not say where the pointer is
+			x, y, _ := sdl.GetMouseState()
+			var me wde.MouseMovedEvent
+			me.Source = w
+			me.When = when
+			me.Mods = modifiersForKeymod(uint16(sdl.GetModState()))
+			me.Where = image.Pt(int(x), int(y))
+			me.From = w.moved(me.Where)
+			if e.Event == sdl.WINDOWEVENT_ENTER {
+				w.send(wde.MouseEnteredEvent(me))
+			} else {
+				w.send(wde.MouseExitedEvent(me))
+			}
+		case sdl.WINDOWEVENT_SIZE_CHANGED, sdl.WINDOWEVENT_RESIZED:
+			// SDL sends SIZE_CHANGED for every change, and RESIZED after
+			// it for those the user made, so only the first is news
+			width, height := int(e.Data1), int(e.Data2)
+			if w.buffer.Bounds().Size() == image.Pt(width, height) {
+				break
+			}
+			w.width, w.height = width, height
+			w.buffer = NewSdlBuffer(width, height)
+			var rev wde.ResizeEvent
+			rev.Source = w
+			rev.When = when
+			rev.Width = width
+			rev.Height = height
+			w.send(rev)
+			w.rescaled(when)
 		case sdl.WINDOWEVENT_CLOSE:
-			log.Println("Close the window please.")
-			events <- &wde.CloseEvent{}
-		case sdl.WINDOWEVENT_FOCUS_GAINED:
-			log.Println("Focus gained, woot!")
-		case sdl.WINDOWEVENT_FOCUS_LOST:
-			log.Println("Focus lost, must have ADHD.")
+			var ce wde.CloseEvent
+			ce.Source = w
+			ce.When = when
+			w.send(ce)
+		case sdl.WINDOWEVENT_FOCUS_GAINED, sdl.WINDOWEVENT_FOCUS_LOST:
+			var fe wde.FocusEvent
+			fe.Source = w
+			fe.When = when
+			fe.Gained = e.Event == sdl.WINDOWEVENT_FOCUS_GAINED
+			if !fe.Gained {
+				// the key releases will go to another window
+				w.keychords = make(map[string]bool)
+			}
+			w.send(fe)
 		case sdl.WINDOWEVENT_MOVED:
-			log.Printf("please move window to %d %d.\n", e.Data1, e.Data2)
+			var mev wde.MoveEvent
+			mev.Source = w
+			mev.When = when
+			mev.X = int(e.Data1)
+			mev.Y = int(e.Data2)
+			w.send(mev)
+			w.rescaled(when)
 		default:
 			log.Printf("UNRECOGNIZED WINDOW EVENT: %d\n", e.Event)
 		}
@@ -270,22 +540,311 @@ func collectEvents() bool {
 	return false
 }
 
+// send queues e for the application, without waiting for it to be read.
+func (w *Window) send(e wde.Event) {
+	w.queueLock.Lock()
+	w.queue = append(w.queue, e)
+	w.queueLock.Unlock()
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// pump moves queued events onto the event channel, and closes it once the
+// window has closed.
+func (w *Window) pump() {
+	defer close(w.events)
+	for {
+		w.queueLock.Lock()
+		if len(w.queue) == 0 {
+			w.queueLock.Unlock()
+			select {
+			case <-w.wake:
+				continue
+			case <-w.done:
+				return
+			}
+		}
+		e := w.queue[0]
+		w.queue = w.queue[1:]
+		w.queueLock.Unlock()
+
+		select {
+		case w.events <- e:
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// Screens lists SDL's video displays. SDL does not say which is primary,
+// but it numbers it 0, so that one is marked.
+func Screens() ([]wde.Screen, error) {
+	ch := make(chan []wde.Screen)
+	screensCh <- ch
+	return <-ch, nil
+}
+
+func listScreens() (screens []wde.Screen) {
+	for i := 0; i < sdl.GetNumVideoDisplays(); i++ {
+		var r sdl.Rect
+		if sdl.GetDisplayBounds(i, &r) != 0 {
+			continue
+		}
+		var s wde.Screen
+		s.Name = sdl.GetDisplayName(i)
+		s.Bounds = image.Rect(int(r.X), int(r.Y), int(r.X+r.W), int(r.Y+r.H))
+		// SDL cannot tell what panels take up
+		s.WorkArea = s.Bounds
+		var mode sdl.DisplayMode
+		if sdl.GetCurrentDisplayMode(i, &mode) == 0 {
+			s.RefreshRate = float64(mode.RefreshRate)
+		}
+		// SDL cannot tell the density of a display apart from a window on it
+		s.Scale = 1
+		s.Primary = i == 0
+		screens = append(screens, s)
+	}
+	return
+}
+
+// moved records that the pointer is now at where, and returns where it was
+// before, or where itself if it had not been seen yet.
+func (w *Window) moved(where image.Point) (from image.Point) {
+	from = where
+	if w.seenPointer {
+		from = w.last
+	}
+	w.last = where
+	w.seenPointer = true
+	return
+}
+
+// cString returns the NUL terminated string at the start of b.
+func cString(b []byte) string {
+	for i, c := range b {
+		if c == 0 {
+			return string(b[:i])
+		}
+	}
+	return string(b)
+}
+
+func buttonForSDL(button uint8) wde.Button {
+	switch button {
+	case sdl.BUTTON_LEFT:
+		return wde.LeftButton
+	case sdl.BUTTON_MIDDLE:
+		return wde.MiddleButton
+	case sdl.BUTTON_RIGHT:
+		return wde.RightButton
+	}
+	return 0
+}
+
+// buttonsForState returns the buttons held in a motion event's state.
+func buttonsForState(state uint32) (which wde.Button) {
+	if state&sdl.BUTTON_LMASK != 0 {
+		which |= wde.LeftButton
+	}
+	if state&sdl.BUTTON_MMASK != 0 {
+		which |= wde.MiddleButton
+	}
+	if state&sdl.BUTTON_RMASK != 0 {
+		which |= wde.RightButton
+	}
+	return
+}
+
+func modifiersForKeymod(mod uint16) (mods wde.Modifiers) {
+	if mod&sdl.KMOD_SHIFT != 0 {
+		mods |= wde.ModShift
+	}
+	if mod&sdl.KMOD_CTRL != 0 {
+		mods |= wde.ModControl
+	}
+	if mod&sdl.KMOD_ALT != 0 {
+		mods |= wde.ModAlt
+	}
+	if mod&sdl.KMOD_GUI != 0 {
+		mods |= wde.ModSuper
+	}
+	if mod&sdl.KMOD_CAPS != 0 {
+		mods |= wde.ModCapsLock
+	}
+	if mod&sdl.KMOD_NUM != 0 {
+		mods |= wde.ModNumLock
+	}
+	return
+}
+
+// windowForID finds the open window SDL knows by id.
+func windowForID(id uint32) *Window {
+	for _, w := range windowList {
+		if w.w != nil && w.w.GetID() == id {
+			return w
+		}
+	}
+	return nil
+}
+
+// flush uploads the parts of the buffer named by flushRects into the
+// streaming texture and presents it. If the buffer has changed size since
+// the texture was made, a new texture is made and all of it is uploaded.
+func (w *Window) flush() {
+	rects := w.flushRects
+	size := w.buffer.Bounds().Size()
+	if w.tex == nil || w.texSize != size {
+		if w.tex != nil {
+			w.tex.Destroy()
+		}
+		// ABGR8888 is packed little end first, so its bytes go R, G, B, A
+		// just like an image.RGBA's
+		w.tex = w.r.CreateTexture(sdl.PIXELFORMAT_ABGR8888, sdl.TEXTUREACCESS_STREAMING, size.X, size.Y)
+		if w.tex == nil {
+			log.Println("Could not create texture:", sdl.GetError())
+			return
+		}
+		w.texSize = size
+		rects = []image.Rectangle{w.buffer.Bounds()}
+	}
+	for _, r := range rects {
+		r = r.Intersect(w.buffer.Bounds())
+		if r.Empty() {
+			continue
+		}
+		sr := sdl.Rect{X: int32(r.Min.X), Y: int32(r.Min.Y), W: int32(r.Dx()), H: int32(r.Dy())}
+		pix := w.buffer.Pix[w.buffer.PixOffset(r.Min.X, r.Min.Y):]
+		w.tex.Update(&sr, unsafe.Pointer(&pix[0]), w.buffer.Stride)
+	}
+	w.present()
+}
+
+// present copies the last flushed frame to the window. SDL leaves the
+// back buffer undefined after each Present, so the whole texture is copied
+// every time; only the upload in flush is limited to what changed.
+func (w *Window) present() {
+	if w.tex == nil {
+		return
+	}
+	dst := sdl.Rect{W: int32(w.texSize.X), H: int32(w.texSize.Y)}
+	w.r.SetDrawColor(0, 0, 0, 0xff)
+	w.r.Clear()
+	w.r.Copy(w.tex, nil, &dst)
+	w.r.Present()
+}
+
 func (w *Window) setupWindow() error {
-	window := sdl.CreateWindow("", sdl.WINDOWPOS_UNDEFINED, sdl.WINDOWPOS_UNDEFINED, w.width, w.height, sdl.WINDOW_SHOWN)
+	x, y := sdl.WINDOWPOS_UNDEFINED, sdl.WINDOWPOS_UNDEFINED
+	if w.opts.Position != nil {
+		x, y = w.opts.Position.X, w.opts.Position.Y
+	}
+	// the window stays hidden until Show
+	var flags uint32 = sdl.WINDOW_HIDDEN
+	if w.opts.Undecorated {
+		flags |= sdl.WINDOW_BORDERLESS
+	}
+	switch w.opts.State {
+	case wde.StateMaximized:
+		flags |= sdl.WINDOW_MAXIMIZED
+	case wde.StateMinimized:
+		flags |= sdl.WINDOW_MINIMIZED
+	case wde.StateFullscreen:
+		flags |= sdl.WINDOW_FULLSCREEN_DESKTOP
+		w.fullscreen = true
+	}
+	window := sdl.CreateWindow(w.title, x, y, w.width, w.height, flags)
 	if window == nil {
 		return sdl.GetError()
 	}
+	w.state = w.opts.State
 
 	renderer := sdl.CreateRenderer(window, -1, sdl.RENDERER_ACCELERATED)
 	if renderer == nil {
-		return sdl.GetError()
+		err := sdl.GetError()
+		window.Destroy()
+		return err
 	}
 
 	w.w = window
 	w.r = renderer
+	w.scale = w.outputScale()
 	return nil
 }
 
+/*
+outputScale returns the ratio of the renderer's size in pixels to the
+window's size as SDL measures it. They differ where SDL measures windows in
+points, as on OS X.
+*/
+func (w *Window) outputScale() float64 {
+	ow, _, err := w.r.GetOutputSize()
+	ww, _ := w.w.GetSize()
+	if err != nil || ow == 0 || ww == 0 {
+		return 1
+	}
+	return float64(ow) / float64(ww)
+}
+
+// rescaled sends a ScaleChangedEvent if the output scale has changed.
+func (w *Window) rescaled(when time.Duration) {
+	scale := w.outputScale()
+	if scale == w.scale {
+		return
+	}
+	w.scale = scale
+	var sev wde.ScaleChangedEvent
+	sev.Source = w
+	sev.When = when
+	sev.Scale = scale
+	w.send(sev)
+}
+
+// applyFullscreen puts the window in or out of fullscreen, on the SDL
+// thread. SDL sends no window event for either, so they are reported here.
+func (w *Window) applyFullscreen() {
+	if !w.fullscreen {
+		if w.state == wde.StateFullscreen && w.w.SetFullscreen(0) == 0 {
+			w.setState(wde.StateNormal, clock.Now())
+		}
+		return
+	}
+	if w.w.SetFullscreen(sdl.WINDOW_FULLSCREEN_DESKTOP) == 0 {
+		w.setState(wde.StateFullscreen, clock.Now())
+	}
+}
+
+// applyState asks SDL for wantState, on the SDL thread. Restoring a
+// fullscreen window takes it out of fullscreen.
+func (w *Window) applyState() {
+	if w.state == wde.StateFullscreen && w.wantState != wde.StateMinimized {
+		w.fullscreen = false
+		w.applyFullscreen()
+	}
+	switch w.wantState {
+	case wde.StateMaximized:
+		w.w.Maximize()
+	case wde.StateMinimized:
+		w.w.Minimize()
+	case wde.StateNormal:
+		w.w.Restore()
+	}
+}
+
+// setState sends a WindowStateEvent if state is not what was last sent.
+func (w *Window) setState(state wde.WindowState, when time.Duration) {
+	if state == w.state {
+		return
+	}
+	w.state = state
+	var se wde.WindowStateEvent
+	se.Source = w
+	se.When = when
+	se.State = state
+	w.send(se)
+}
+
 /*
 func (w *Window) manageThread(width, height int, ready chan error) {
 	runtime.LockOSThread()
@@ -335,6 +894,15 @@ func (w *Window) manageThread(width, height int, ready chan error) {
 }
 */
 
+// scancodeForSDL returns the physical key of an SDL scancode. SDL numbers
+// the keys of the keyboard page by their USB HID usages already.
+func scancodeForSDL(code sdl.Scancode) wde.Scancode {
+	if code < 4 || code > 0xe7 {
+		return 0
+	}
+	return wde.Scancode(code)
+}
+
 func ConvertKeyCode(key sdl.Scancode) string {
 	//v, ok := keyMap[key]
 	if int(key) >= len(keys) || key < 4 {
@@ -0,0 +1,326 @@
+/*
+   Copyright 2012 the go.wde authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+/*
+Package shortcut binds keyboard shortcuts to functions and runs them as
+the matching keys are typed into a window.
+
+A shortcut is written as one or more strokes separated by spaces, each
+stroke being keys joined with "+", as in "control+shift+s" or the two
+stroke "control+k control+c". Keys are named by the wde key constants,
+with the modifiers written "super", "shift", "alt", "control" and
+"function", whichever side of the keyboard they are on. "mod" stands for
+the platform's usual modifier for shortcuts: super on OS X, control
+elsewhere.
+
+	m := shortcut.NewMap()
+	m.Bind("mod+s", func(wde.KeyTypedEvent) { save() })
+	m.Bind("control+k control+c", func(wde.KeyTypedEvent) { comment() })
+	for e := range m.Filter(w.EventChan()) {
+		// every event not taken by a shortcut
+	}
+*/
+package shortcut
+
+import (
+	"errors"
+	"fmt"
+	"github.com/skelterjohn/go.wde"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Primary is the modifier that "mod" stands for.
+var Primary = "control"
+
+func init() {
+	if runtime.GOOS == "darwin" {
+		Primary = "super"
+	}
+}
+
+var modifiers = map[string]bool{
+	"super":    true,
+	"shift":    true,
+	"alt":      true,
+	"control":  true,
+	"function": true,
+}
+
+// aliases are the other names Parse accepts for the modifiers.
+var aliases = map[string]string{
+	"ctrl":    "control",
+	"cmd":     "super",
+	"command": "super",
+	"option":  "alt",
+	"fn":      "function",
+}
+
+var ErrConflict = errors.New("shortcut: binding is a prefix of another binding")
+
+/*
+A Shortcut is a parsed shortcut, one chord per stroke. Each chord is in the
+form wde.ConstructChord gives KeyTypedEvent.Chord, or is a single key when
+the stroke is one key alone.
+*/
+type Shortcut []string
+
+// Parse parses s into a Shortcut, putting the keys of each stroke into
+// wde.ChordSorter order.
+func Parse(s string) (sc Shortcut, err error) {
+	strokes := strings.Fields(strings.ToLower(s))
+	if len(strokes) == 0 {
+		err = fmt.Errorf("shortcut: %q is empty", s)
+		return
+	}
+	for _, stroke := range strokes {
+		var chord string
+		chord, err = parseStroke(stroke)
+		if err != nil {
+			return
+		}
+		sc = append(sc, chord)
+	}
+	return
+}
+
+func parseStroke(stroke string) (chord string, err error) {
+	keys := strings.Split(stroke, "+")
+	for i, key := range keys {
+		if a, ok := aliases[key]; ok {
+			key = a
+		}
+		if key == "mod" {
+			key = Primary
+		}
+		if !modifiers[key] && !wde.KnownKey(key) {
+			err = fmt.Errorf("shortcut: unknown key %q in %q", key, stroke)
+			return
+		}
+		keys[i] = key
+	}
+	if len(keys) == 1 {
+		if modifiers[keys[0]] || wde.ModifierForKey(keys[0]) != 0 {
+			err = fmt.Errorf("shortcut: %q is a modifier alone", stroke)
+			return
+		}
+		chord = keys[0]
+		return
+	}
+
+	// wde.ConstructChord drops the sides from modifier key names; so must we
+	seen := map[string]bool{}
+	for i, key := range keys {
+		if wde.ModifierForKey(key) != 0 {
+			key = strings.TrimPrefix(strings.TrimPrefix(key, "left_"), "right_")
+		}
+		if seen[key] {
+			err = fmt.Errorf("shortcut: %q repeats %q", stroke, key)
+			return
+		}
+		seen[key] = true
+		keys[i] = key
+	}
+	sort.Sort(wde.ChordSorter(keys))
+	chord = strings.Join(keys, "+")
+	return
+}
+
+// String returns the shortcut with its strokes separated by spaces, in the
+// form Parse accepts.
+func (sc Shortcut) String() string {
+	return strings.Join(sc, " ")
+}
+
+// stroke returns what e contributes to a shortcut: its chord, or its key
+// when it was typed alone. Modifier keys typed alone contribute nothing.
+func stroke(e wde.KeyTypedEvent) string {
+	if e.Chord != "" {
+		return e.Chord
+	}
+	if wde.ModifierForKey(e.Key) != 0 || e.Key == wde.KeyFunction {
+		return ""
+	}
+	return e.Key
+}
+
+// DefaultTimeout is the Timeout of the maps NewMap makes.
+var DefaultTimeout = 2 * time.Second
+
+/*
+A Map holds a set of shortcut bindings and tracks the strokes typed so far
+of any multi-stroke shortcut. Its methods may be called from any goroutine;
+the bound functions run on the goroutine that calls Dispatch.
+*/
+type Map struct {
+	// Timeout is how soon, by the events' timestamps, each stroke of a
+	// multi-stroke shortcut must follow the one before; a later stroke
+	// abandons those before it. Zero means there is no limit. Set it before
+	// the map is used.
+	Timeout time.Duration
+
+	lock     sync.Mutex
+	bindings map[string]func(wde.KeyTypedEvent)
+	// prefixes counts the bindings each proper prefix begins
+	prefixes map[string]int
+	pending  Shortcut
+	// last is when the last pending stroke was typed
+	last time.Duration
+}
+
+func NewMap() (m *Map) {
+	m = &Map{
+		Timeout:  DefaultTimeout,
+		bindings: map[string]func(wde.KeyTypedEvent){},
+		prefixes: map[string]int{},
+	}
+	return
+}
+
+/*
+Bind parses s and binds it to f, replacing any earlier binding of the same
+shortcut. It returns ErrConflict if s begins another binding or another
+binding begins s, since the shorter one would always fire first.
+*/
+func (m *Map) Bind(s string, f func(wde.KeyTypedEvent)) (err error) {
+	sc, err := Parse(s)
+	if err != nil {
+		return
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	key := sc.String()
+	if _, ok := m.bindings[key]; ok {
+		m.bindings[key] = f
+		return
+	}
+	if m.prefixes[key] != 0 {
+		return ErrConflict
+	}
+	for i := 1; i < len(sc); i++ {
+		if _, ok := m.bindings[sc[:i].String()]; ok {
+			return ErrConflict
+		}
+	}
+	m.bindings[key] = f
+	for i := 1; i < len(sc); i++ {
+		m.prefixes[sc[:i].String()]++
+	}
+	return
+}
+
+// Unbind removes the binding of s, if there is one.
+func (m *Map) Unbind(s string) (err error) {
+	sc, err := Parse(s)
+	if err != nil {
+		return
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	key := sc.String()
+	if _, ok := m.bindings[key]; !ok {
+		return
+	}
+	delete(m.bindings, key)
+	for i := 1; i < len(sc); i++ {
+		p := sc[:i].String()
+		if m.prefixes[p]--; m.prefixes[p] == 0 {
+			delete(m.prefixes, p)
+		}
+	}
+	m.pending = nil
+	return
+}
+
+/*
+Dispatch feeds e to the map, and reports whether a shortcut took it. A
+KeyTypedEvent that completes a shortcut runs the bound function; one that
+begins or continues a multi-stroke shortcut is held until the shortcut is
+complete. A stroke that leads nowhere abandons the strokes before it and is
+tried again on its own. Losing the focus, or taking longer than Timeout to
+type the next stroke, abandons them too.
+*/
+func (m *Map) Dispatch(e wde.Event) (handled bool) {
+	var f func(wde.KeyTypedEvent)
+	var ke wde.KeyTypedEvent
+	m.lock.Lock()
+	switch e := e.(type) {
+	case wde.KeyTypedEvent:
+		s := stroke(e)
+		if s == "" {
+			break
+		}
+		ke = e
+		if m.pending != nil && m.Timeout != 0 && e.When-m.last > m.Timeout {
+			m.pending = nil
+		}
+		f, handled = m.feed(s)
+		if !handled && m.pending != nil {
+			m.pending = nil
+			f, handled = m.feed(s)
+		}
+		m.last = e.When
+	case wde.FocusEvent:
+		if !e.Gained {
+			m.pending = nil
+		}
+	}
+	m.lock.Unlock()
+
+	if f != nil {
+		f(ke)
+	}
+	return
+}
+
+// feed adds s to the pending strokes, returning the function to run if that
+// completes a shortcut.
+func (m *Map) feed(s string) (f func(wde.KeyTypedEvent), handled bool) {
+	seq := append(m.pending[:len(m.pending):len(m.pending)], s)
+	key := seq.String()
+	if f, handled = m.bindings[key]; handled {
+		m.pending = nil
+		return
+	}
+	if m.prefixes[key] != 0 {
+		m.pending = seq
+		handled = true
+	}
+	return
+}
+
+/*
+Filter dispatches every event read from events, usually a window's
+EventChan, and passes on those that no shortcut took. The returned channel
+is closed once events is.
+*/
+func (m *Map) Filter(events <-chan wde.Event) <-chan wde.Event {
+	out := make(chan wde.Event)
+	go func() {
+		defer close(out)
+		for e := range events {
+			if !m.Dispatch(e) {
+				out <- e
+			}
+		}
+	}()
+	return out
+}
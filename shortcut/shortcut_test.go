@@ -0,0 +1,167 @@
+/*
+   Copyright 2012 the go.wde authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package shortcut
+
+import (
+	"github.com/skelterjohn/go.wde"
+	"testing"
+	"time"
+)
+
+// typed returns the KeyTypedEvent a backend sends when the last of keys is
+// pressed with the others held.
+func typed(when time.Duration, keys ...string) (e wde.KeyTypedEvent) {
+	down := map[string]bool{}
+	for _, key := range keys {
+		down[key] = true
+	}
+	e.When = when
+	e.Key = keys[len(keys)-1]
+	e.Chord = wde.ConstructChord(down)
+	return
+}
+
+func TestParse(t *testing.T) {
+	defer func(p string) { Primary = p }(Primary)
+	Primary = "super"
+
+	for _, test := range []struct {
+		s    string
+		want string // empty if s does not parse
+	}{
+		{"s", "s"},
+		{"control+shift+s", "shift+control+s"},
+		{"Ctrl+S", "control+s"},
+		{"cmd+option+q", "super+alt+q"},
+		{"mod+s", "super+s"},
+		{"left_shift+right_control+a", "shift+control+a"},
+		{"control+left_arrow", "control+left_arrow"},
+		{"control+k  control+c", "control+k control+c"},
+		{"", ""},
+		{"shift", ""},
+		{"left_control", ""},
+		{"control+nosuchkey", ""},
+		{"shift+left_shift+a", ""},
+	} {
+		sc, err := Parse(test.s)
+		if test.want == "" {
+			if err == nil {
+				t.Errorf("Parse(%q) = %q, want an error", test.s, sc)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q): %v", test.s, err)
+		} else if sc.String() != test.want {
+			t.Errorf("Parse(%q) = %q, want %q", test.s, sc, test.want)
+		}
+	}
+}
+
+func TestDispatch(t *testing.T) {
+	defer func(p string) { Primary = p }(Primary)
+	Primary = "control"
+
+	var fired []string
+	m := NewMap()
+	for _, s := range []string{
+		"mod+s",
+		"control+left_arrow",
+		"control+right_arrow",
+		"control+k control+c",
+		"f1",
+	} {
+		s := s
+		if err := m.Bind(s, func(wde.KeyTypedEvent) { fired = append(fired, s) }); err != nil {
+			t.Fatalf("Bind(%q): %v", s, err)
+		}
+	}
+
+	for _, test := range []struct {
+		e       wde.KeyTypedEvent
+		handled bool
+		fired   string
+	}{
+		{typed(0, wde.KeyLeftControl, wde.KeyS), true, "mod+s"},
+		{typed(0, wde.KeyRightControl, wde.KeyS), true, "mod+s"},
+		{typed(0, wde.KeyLeftSuper, wde.KeyS), false, ""},
+		{typed(0, wde.KeyS), false, ""},
+		{typed(0, wde.KeyLeftControl), false, ""},
+		{typed(0, wde.KeyF1), true, "f1"},
+		{typed(0, wde.KeyRightControl, wde.KeyLeftArrow), true, "control+left_arrow"},
+		{typed(0, wde.KeyLeftControl, wde.KeyRightArrow), true, "control+right_arrow"},
+		// a sequence, typed in time
+		{typed(0, wde.KeyLeftControl, wde.KeyK), true, ""},
+		{typed(time.Second, wde.KeyLeftControl, wde.KeyC), true, "control+k control+c"},
+		// a sequence broken by a stroke that leads nowhere, which is then
+		// tried on its own
+		{typed(0, wde.KeyLeftControl, wde.KeyK), true, ""},
+		{typed(0, wde.KeyF1), true, "f1"},
+		{typed(0, wde.KeyLeftControl, wde.KeyC), false, ""},
+		// a sequence typed too slowly
+		{typed(0, wde.KeyLeftControl, wde.KeyK), true, ""},
+		{typed(DefaultTimeout+time.Millisecond, wde.KeyLeftControl, wde.KeyC), false, ""},
+		// a sequence whose second stroke begins the sequence again
+		{typed(0, wde.KeyLeftControl, wde.KeyK), true, ""},
+		{typed(0, wde.KeyLeftControl, wde.KeyK), true, ""},
+		{typed(0, wde.KeyLeftControl, wde.KeyC), true, "control+k control+c"},
+	} {
+		fired = nil
+		if handled := m.Dispatch(test.e); handled != test.handled {
+			t.Errorf("Dispatch(%q at %v) = %v, want %v", test.e.Chord, test.e.When, handled, test.handled)
+		}
+		switch {
+		case len(fired) > 1:
+			t.Errorf("%q at %v fired %q", test.e.Chord, test.e.When, fired)
+		case len(fired) == 1 && fired[0] != test.fired:
+			t.Errorf("%q at %v fired %q, want %q", test.e.Chord, test.e.When, fired[0], test.fired)
+		case len(fired) == 0 && test.fired != "":
+			t.Errorf("%q at %v fired nothing, want %q", test.e.Chord, test.e.When, test.fired)
+		}
+	}
+}
+
+func TestFocusLostAbandonsSequence(t *testing.T) {
+	fired := false
+	m := NewMap()
+	m.Bind("control+k control+c", func(wde.KeyTypedEvent) { fired = true })
+	m.Dispatch(typed(0, wde.KeyLeftControl, wde.KeyK))
+	m.Dispatch(wde.FocusEvent{})
+	if m.Dispatch(typed(0, wde.KeyLeftControl, wde.KeyC)) || fired {
+		t.Error("sequence completed across a loss of focus")
+	}
+}
+
+func TestBindConflict(t *testing.T) {
+	m := NewMap()
+	if err := m.Bind("control+k control+c", func(wde.KeyTypedEvent) {}); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"control+k", "control+k control+c control+x"} {
+		if err := m.Bind(s, func(wde.KeyTypedEvent) {}); err != ErrConflict {
+			t.Errorf("Bind(%q) = %v, want ErrConflict", s, err)
+		}
+	}
+	// rebinding the same shortcut, however written, replaces it
+	if err := m.Bind("ctrl+K ctrl+C", func(wde.KeyTypedEvent) {}); err != nil {
+		t.Errorf("rebinding: %v", err)
+	}
+	m.Unbind("control+k control+c")
+	if err := m.Bind("control+k", func(wde.KeyTypedEvent) {}); err != nil {
+		t.Errorf("Bind after Unbind: %v", err)
+	}
+}
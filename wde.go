@@ -25,14 +25,58 @@ type Window interface {
 	SetTitle(title string)
 	SetSize(width, height int)
 	Size() (width, height int)
+	// Position reports where the top-left corner of the window is on the
+	// screen. Most backends include the frame the window system draws
+	// around the window.
+	Position() (x, y int)
+	// SetPosition moves the window's top-left corner to x, y on the screen,
+	// measured as Position measures it, so SetPosition(w.Position()) leaves
+	// the window in place.
+	SetPosition(x, y int)
+	// Center moves the window to the middle of the screen it is on.
+	Center()
 	LockSize(lock bool)
+	// Scale reports how many of Screen's pixels make up one pixel at the
+	// density applications are usually designed for, as 2 does on most
+	// HiDPI displays. Sizes and positions stay in pixels; applications draw
+	// their text and widgets Scale times larger to keep them legible.
+	Scale() float64
 	Show()
+	// SetFullscreen makes the window cover the whole of its screen, without
+	// a frame, or puts it back as it was.
+	SetFullscreen(fullscreen bool)
+	Maximize()
+	Minimize()
+	// Restore puts a maximized, minimized or fullscreen window back to its
+	// normal size and place.
+	Restore()
 	Screen() (im Image)
+	// FlushImage shows what has been drawn to the screen. If bounds are
+	// given, only those parts of the window are updated.
 	FlushImage(bounds ...image.Rectangle)
-	EventChan() (events <-chan interface{})
+	EventChan() (events <-chan Event)
 	Close() (err error)
 }
 
+/*
+FlushRects returns the parts of a screen with the given bounds that a call
+to FlushImage(flush...) should update: all of it if flush is empty,
+otherwise each rectangle of flush clipped to the screen, leaving out those
+that end up empty. Backends use it to honor FlushImage's bounds.
+*/
+func FlushRects(screen image.Rectangle, flush []image.Rectangle) (rects []image.Rectangle) {
+	if len(flush) == 0 {
+		return []image.Rectangle{screen}
+	}
+	for _, r := range flush {
+		r = r.Intersect(screen)
+		if !r.Empty() {
+			rects = append(rects, r)
+		}
+	}
+	return
+}
+
 type Image interface {
 	draw.Image
 	// CopyRGBA() copies the source image to this image, translating
@@ -68,6 +112,9 @@ or
 
 	import _ "github.com/skelterjohn/go.wde/cocoa"
 
+or, for tests that should run without a display,
+
+	import _ "github.com/skelterjohn/go.wde/headless"
 
 will register a backend with go.wde, allowing you to call
 wde.Run(), wde.Stop() and wde.NewWindow() without referring to the
@@ -103,7 +150,8 @@ var BackendStop = func() {
 }
 
 /*
-Create a new window with the specified width and height.
+Create a new window with the specified width and height. Use
+NewWindowWithOptions to set up more of the window before it is shown.
 */
 func NewWindow(width, height int) (Window, error) {
 	return BackendNewWindow(width, height)
@@ -0,0 +1,177 @@
+/*
+   Copyright 2012 the go.wde authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"github.com/skelterjohn/go.wde"
+	_ "github.com/skelterjohn/go.wde/init"
+	"image"
+	"image/color"
+	"math/rand"
+	"runtime"
+	"sync"
+	"time"
+)
+
+func main() {
+	go wdetest()
+	wde.Run()
+
+	println("done")
+}
+
+func wdetest() {
+	var wg sync.WaitGroup
+
+	screens, err := wde.Screens()
+	if err != nil {
+		fmt.Println(err)
+	}
+	for _, s := range screens {
+		fmt.Println("screen", s.Name, s.Bounds, s.WorkArea, s.DPI, s.RefreshRate, s.Scale, s.Primary)
+	}
+
+	size := 200
+
+	x := func() {
+		offset := time.Duration(rand.Intn(1e9))
+
+		dw, err := wde.NewWindowWithOptions(wde.WindowOptions{
+			Width:   size,
+			Height:  size,
+			Title:   "hi!",
+			MinSize: image.Pt(100, 100),
+		})
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		dw.Show()
+
+		events := dw.EventChan()
+
+		done := make(chan bool)
+
+		go func() {
+		loop:
+			for ei := range events {
+				runtime.Gosched()
+				switch e := ei.(type) {
+				case wde.MouseDownEvent:
+					fmt.Println("clicked", e.Where.X, e.Where.Y, e.Which, e.Clicks)
+					// dw.Close()
+					// break loop
+				case wde.MouseUpEvent:
+				case wde.MouseMovedEvent:
+				case wde.MouseDraggedEvent:
+				case wde.MouseEnteredEvent:
+					fmt.Println("mouse entered", e.Where.X, e.Where.Y)
+				case wde.MouseExitedEvent:
+					fmt.Println("mouse exited", e.Where.X, e.Where.Y)
+				case wde.ScrollEvent:
+					fmt.Println("scrolled", e.Where.X, e.Where.Y, e.DeltaX, e.DeltaY, e.Precise)
+				case wde.KeyDownEvent:
+					// fmt.Println("KeyDownEvent", e.Glyph)
+				case wde.KeyUpEvent:
+					// fmt.Println("KeyUpEvent", e.Glyph)
+				case wde.KeyTypedEvent:
+					fmt.Println("typed", e.Key, e.Glyph, e.Chord)
+				case wde.TextInputEvent:
+					fmt.Printf("text %q\n", e.Text)
+				case wde.TextCompositionEvent:
+					fmt.Printf("composing %q at %d\n", e.Text, e.Cursor)
+				case wde.FocusEvent:
+					fmt.Println("focus", e.Gained)
+				case wde.ExposeEvent:
+					fmt.Println("exposed", e.Rects)
+				case wde.CloseEvent:
+					fmt.Println("close")
+					dw.Close()
+					break loop
+				case wde.ResizeEvent:
+					fmt.Println("resize", e.Width, e.Height)
+				case wde.MoveEvent:
+					fmt.Println("move", e.X, e.Y)
+				case wde.ScaleChangedEvent:
+					fmt.Println("scale", e.Scale)
+				case wde.WindowStateEvent:
+					fmt.Println("state", e.State)
+				}
+			}
+			done <- true
+			fmt.Println("end of events")
+		}()
+
+		for i := 0; ; i++ {
+			width, height := dw.Size()
+			s := dw.Screen()
+			for x := 0; x < width; x++ {
+				for y := 0; y < height; y++ {
+					s.Set(x, y, color.White)
+				}
+			}
+			for x := 0; x < width; x++ {
+				for y := 0; y < height; y++ {
+					var r uint8
+					if x > width/2 {
+						r = 255
+					}
+					var g uint8
+					if y >= height/2 {
+						g = 255
+					}
+					var b uint8
+					if y < height/4 || y >= height*3/4 {
+						b = 255
+					}
+					if i%2 == 1 {
+						r = 255 - r
+					}
+
+					if y > height-10 {
+						r = 255
+						g = 255
+						b = 255
+					}
+
+					if x == y {
+						r = 100
+						g = 100
+						b = 100
+					}
+
+					s.Set(x, y, color.RGBA{r, g, b, 255})
+				}
+			}
+			dw.FlushImage()
+			select {
+			case <-time.After(5e8 + offset):
+			case <-done:
+				wg.Done()
+				return
+			}
+		}
+	}
+	wg.Add(1)
+	go x()
+	wg.Add(1)
+	go x()
+
+	wg.Wait()
+	wde.Stop()
+}
@@ -0,0 +1,170 @@
+/*
+   Copyright 2012 the go.wde authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package wdetest
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Golden compares frames against golden PNG files.
+type Golden struct {
+	// Tolerance is the largest difference, per 8-bit channel, for which two
+	// pixels still count as the same.
+	Tolerance uint8
+	// MaxPixels is the number of pixels allowed to differ by more than
+	// Tolerance before the comparison fails.
+	MaxPixels int
+	// DiffDir is where the actual frame and a diff image are written when a
+	// comparison fails. If empty, they are written next to the golden file.
+	DiffDir string
+	// Update makes Compare write the frame as the new golden file instead of
+	// comparing against it.
+	Update bool
+}
+
+// DefaultGolden demands an exact match, and updates golden files instead
+// when the WDETEST_UPDATE environment variable is set.
+var DefaultGolden = Golden{
+	Update: os.Getenv("WDETEST_UPDATE") != "",
+}
+
+// A MismatchError reports a frame that did not match its golden file.
+type MismatchError struct {
+	Golden string
+	// Pixels is the number of pixels beyond tolerance, or -1 if the sizes
+	// differ.
+	Pixels int
+	// Actual and Diff are the files written to show the failure. Diff is
+	// empty if the sizes differ.
+	Actual, Diff string
+}
+
+func (e *MismatchError) Error() string {
+	if e.Pixels < 0 {
+		return fmt.Sprintf("wdetest: frame size differs from %s (actual frame in %s)", e.Golden, e.Actual)
+	}
+	return fmt.Sprintf("wdetest: %d pixels differ from %s (see %s)", e.Pixels, e.Golden, e.Diff)
+}
+
+// Compare checks im against the PNG at path. On a mismatch it writes the
+// actual frame and an image highlighting the differing pixels in red, and
+// returns a *MismatchError naming them.
+func (g Golden) Compare(im image.Image, path string) (err error) {
+	if im == nil {
+		return errors.New("wdetest: no frame to compare")
+	}
+	if g.Update {
+		return writePNG(path, im)
+	}
+
+	want, err := readPNG(path)
+	if err != nil {
+		return
+	}
+
+	base := strings.TrimSuffix(path, filepath.Ext(path))
+	if g.DiffDir != "" {
+		base = filepath.Join(g.DiffDir, filepath.Base(base))
+	}
+	merr := &MismatchError{
+		Golden: path,
+		Actual: base + ".actual.png",
+	}
+
+	if im.Bounds().Size() != want.Bounds().Size() {
+		merr.Pixels = -1
+		if err = writePNG(merr.Actual, im); err != nil {
+			return
+		}
+		return merr
+	}
+
+	diff, n := g.diff(im, want)
+	if n <= g.MaxPixels {
+		return
+	}
+	merr.Pixels = n
+	merr.Diff = base + ".diff.png"
+	if err = writePNG(merr.Actual, im); err != nil {
+		return
+	}
+	if err = writePNG(merr.Diff, diff); err != nil {
+		return
+	}
+	return merr
+}
+
+// diff returns an image of want, faded to grey, with every pixel of got
+// that is out of tolerance painted red, and the number of such pixels.
+func (g Golden) diff(got, want image.Image) (diff *image.RGBA, n int) {
+	size := want.Bounds().Size()
+	diff = image.NewRGBA(image.Rectangle{image.ZP, size})
+	gmin, wmin := got.Bounds().Min, want.Bounds().Min
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			a := color.RGBAModel.Convert(got.At(gmin.X+x, gmin.Y+y)).(color.RGBA)
+			b := color.RGBAModel.Convert(want.At(wmin.X+x, wmin.Y+y)).(color.RGBA)
+			if g.within(a.R, b.R) && g.within(a.G, b.G) && g.within(a.B, b.B) && g.within(a.A, b.A) {
+				grey := color.GrayModel.Convert(b).(color.Gray).Y
+				grey = 0xff - (0xff-grey)/4
+				diff.SetRGBA(x, y, color.RGBA{grey, grey, grey, 0xff})
+				continue
+			}
+			n++
+			diff.SetRGBA(x, y, color.RGBA{0xff, 0, 0, 0xff})
+		}
+	}
+	return
+}
+
+func (g Golden) within(a, b uint8) bool {
+	if a > b {
+		a, b = b, a
+	}
+	return b-a <= g.Tolerance
+}
+
+func readPNG(path string) (im image.Image, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	return png.Decode(f)
+}
+
+func writePNG(path string, im image.Image) (err error) {
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return
+	}
+	if err = png.Encode(f, im); err != nil {
+		f.Close()
+		return
+	}
+	return f.Close()
+}
@@ -0,0 +1,136 @@
+/*
+   Copyright 2012 the go.wde authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package wdetest
+
+import (
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func solid(w, h int, c color.RGBA) *image.RGBA {
+	im := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			im.SetRGBA(x, y, c)
+		}
+	}
+	return im
+}
+
+func TestGoldenCompare(t *testing.T) {
+	grey := color.RGBA{0x80, 0x80, 0x80, 0xff}
+	// off has its top left pixel 3 away from grey in the red channel, and
+	// off2 its top right pixel too
+	off := solid(4, 4, grey)
+	off.SetRGBA(0, 0, color.RGBA{0x83, 0x80, 0x80, 0xff})
+	off2 := solid(4, 4, grey)
+	off2.SetRGBA(0, 0, color.RGBA{0x83, 0x80, 0x80, 0xff})
+	off2.SetRGBA(3, 0, color.RGBA{0x80, 0x80, 0x7d, 0xff})
+
+	for _, test := range []struct {
+		name   string
+		g      Golden
+		im     image.Image
+		pixels int // 0 for a match, else the MismatchError's Pixels
+	}{
+		{"exact", Golden{}, solid(4, 4, grey), 0},
+		{"within tolerance", Golden{Tolerance: 3}, off, 0},
+		{"over tolerance", Golden{Tolerance: 2}, off, 1},
+		{"within max pixels", Golden{MaxPixels: 2}, off2, 0},
+		{"over max pixels", Golden{MaxPixels: 1}, off2, 2},
+		{"size mismatch", Golden{Tolerance: 0xff}, solid(4, 5, grey), -1},
+	} {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "frame.png")
+		if err := writePNG(path, solid(4, 4, grey)); err != nil {
+			t.Fatal(err)
+		}
+
+		err := test.g.Compare(test.im, path)
+		if test.pixels == 0 {
+			if err != nil {
+				t.Errorf("%s: %v", test.name, err)
+			}
+			continue
+		}
+		merr, ok := err.(*MismatchError)
+		if !ok {
+			t.Errorf("%s: got %v, want a *MismatchError", test.name, err)
+			continue
+		}
+		if merr.Pixels != test.pixels {
+			t.Errorf("%s: %d pixels differ, want %d", test.name, merr.Pixels, test.pixels)
+		}
+		if _, err := readPNG(merr.Actual); err != nil {
+			t.Errorf("%s: actual frame not written: %v", test.name, err)
+		}
+		if test.pixels < 0 {
+			if merr.Diff != "" {
+				t.Errorf("%s: diff %s written for frames of different sizes", test.name, merr.Diff)
+			}
+			continue
+		}
+		diff, err := readPNG(merr.Diff)
+		if err != nil {
+			t.Errorf("%s: diff not written: %v", test.name, err)
+			continue
+		}
+		red := color.RGBA{0xff, 0, 0, 0xff}
+		if c := color.RGBAModel.Convert(diff.At(0, 0)); c != red {
+			t.Errorf("%s: differing pixel is %v in the diff, want red", test.name, c)
+		}
+		if c := color.RGBAModel.Convert(diff.At(1, 1)); c == red {
+			t.Errorf("%s: matching pixel is red in the diff", test.name)
+		}
+	}
+}
+
+func TestGoldenUpdate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "frame.png")
+	im := solid(2, 3, color.RGBA{1, 2, 3, 0xff})
+	if err := (Golden{Update: true}).Compare(im, path); err != nil {
+		t.Fatal(err)
+	}
+	if err := (Golden{}).Compare(im, path); err != nil {
+		t.Errorf("frame does not match the golden file it just wrote: %v", err)
+	}
+}
+
+func TestGoldenDiffDir(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "frame.png")
+	if err := writePNG(path, solid(2, 2, color.RGBA{0, 0, 0, 0xff})); err != nil {
+		t.Fatal(err)
+	}
+	diffDir := filepath.Join(dir, "out")
+	err := Golden{DiffDir: diffDir}.Compare(solid(2, 2, color.RGBA{0xff, 0xff, 0xff, 0xff}), path)
+	merr, ok := err.(*MismatchError)
+	if !ok {
+		t.Fatalf("got %v, want a *MismatchError", err)
+	}
+	for _, f := range []string{merr.Actual, merr.Diff} {
+		if filepath.Dir(f) != diffDir {
+			t.Errorf("%s is not in DiffDir", f)
+		}
+		if _, err := os.Stat(f); err != nil {
+			t.Error(err)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+/*
+   Copyright 2012 the go.wde authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package wdetest
+
+import (
+	"github.com/skelterjohn/go.wde"
+	"image"
+	"strings"
+)
+
+/*
+The functions below build the event sequences a backend would produce for
+common gestures. Their results can be concatenated and handed to
+Window.Play or Window.Step.
+*/
+
+// Click presses and releases which at where.
+func Click(where image.Point, which wde.Button) (events []wde.Event) {
+	return MultiClick(where, which, 1)
+}
+
+// MultiClick clicks which at where n times in quick succession, as in a
+// double click when n is 2.
+func MultiClick(where image.Point, which wde.Button, n int) (events []wde.Event) {
+	for i := 1; i <= n; i++ {
+		var down wde.MouseDownEvent
+		down.Where = where
+		down.Which = which
+		down.Clicks = i
+		var up wde.MouseUpEvent
+		up.Where = where
+		up.Which = which
+		up.Clicks = i
+		events = append(events, down, up)
+	}
+	return
+}
+
+// Move moves the mouse from one point to another without any button held.
+func Move(from, to image.Point) (events []wde.Event) {
+	var me wde.MouseMovedEvent
+	me.Where = to
+	me.From = from
+	return []wde.Event{me}
+}
+
+// Drag presses which at from, drags it to to in the given number of steps,
+// and releases it there.
+func Drag(from, to image.Point, which wde.Button, steps int) (events []wde.Event) {
+	if steps < 1 {
+		steps = 1
+	}
+	var down wde.MouseDownEvent
+	down.Where = from
+	down.Which = which
+	events = append(events, down)
+
+	last := from
+	for i := 1; i <= steps; i++ {
+		var de wde.MouseDraggedEvent
+		de.From = last
+		de.Where = from.Add(to.Sub(from).Mul(i).Div(steps))
+		de.Which = which
+		events = append(events, de)
+		last = de.Where
+	}
+
+	var up wde.MouseUpEvent
+	up.Where = to
+	up.Which = which
+	events = append(events, up)
+	return
+}
+
+// Scroll scrolls by the given number of notches with the pointer at where.
+func Scroll(where image.Point, dx, dy float64) (events []wde.Event) {
+	var se wde.ScrollEvent
+	se.Where = where
+	se.DeltaX, se.DeltaY = dx, dy
+	return []wde.Event{se}
+}
+
+// Press presses and releases a single key, typing glyph. A non-empty glyph
+// is also reported as a wde.TextInputEvent.
+func Press(key, glyph string) (events []wde.Event) {
+	ke := wde.KeyEvent{Key: key}
+	events = []wde.Event{
+		wde.KeyDownEvent{KeyEvent: ke},
+		wde.KeyTypedEvent{KeyEvent: ke, Glyph: glyph},
+	}
+	if glyph != "" {
+		var te wde.TextInputEvent
+		te.Text = glyph
+		events = append(events, te)
+	}
+	return append(events, wde.KeyUpEvent(ke))
+}
+
+// Chord holds down each of mods, presses key, and releases them all again,
+// reporting the chord and modifier state the way the backends do: each
+// event carries the modifiers that were held before it.
+func Chord(key string, mods ...string) (events []wde.Event) {
+	down := map[string]bool{}
+	var held wde.Modifiers
+	for _, m := range mods {
+		ke := wde.KeyEvent{Key: m}
+		ke.Mods = held
+		events = append(events, wde.KeyDownEvent{KeyEvent: ke})
+		down[m] = true
+		held |= wde.ModifierForKey(m)
+	}
+	ke := wde.KeyEvent{Key: key}
+	ke.Mods = held
+	down[key] = true
+	events = append(events,
+		wde.KeyDownEvent{KeyEvent: ke},
+		wde.KeyTypedEvent{KeyEvent: ke, Chord: wde.ConstructChord(down)},
+		wde.KeyUpEvent(ke),
+	)
+	for i := len(mods) - 1; i >= 0; i-- {
+		ke := wde.KeyUpEvent{Key: mods[i]}
+		ke.Mods = held
+		events = append(events, ke)
+		held &^= wde.ModifierForKey(mods[i])
+	}
+	return
+}
+
+// Type presses a key for each character of text. Letters and digits are
+// reported with their own key constant, a space as wde.KeySpace, and
+// anything else with the character itself as the key; no shift key is
+// pressed for capitals.
+func Type(text string) (events []wde.Event) {
+	for _, r := range text {
+		glyph := string(r)
+		key := glyph
+		switch {
+		case r == ' ':
+			key = wde.KeySpace
+		case r == '\n':
+			key, glyph = wde.KeyReturn, ""
+		case r == '\t':
+			key = wde.KeyTab
+		case r < 0x80:
+			key = strings.ToLower(glyph)
+		}
+		events = append(events, Press(key, glyph)...)
+	}
+	return
+}
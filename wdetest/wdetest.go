@@ -14,136 +14,170 @@
    limitations under the License.
 */
 
-package main
+/*
+Package wdetest drives code written against wde.Window from tests.
+
+Wrap any wde.Window (usually one from the headless backend) and hand the
+wrapper to the code under test. The test can then play scripted events
+into it, wait for the application to respond with FlushImage, and compare
+the flushed frame against a golden PNG.
+
+	w := wdetest.Wrap(win)
+	go app.Run(w)
+
+	n := w.FlushCount()
+	w.Play(wdetest.Click(image.Pt(10, 10), wde.LeftButton)...)
+	if err := w.WaitFlush(n, time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := wdetest.DefaultGolden.Compare(w.Frame(), "testdata/click.png"); err != nil {
+		t.Fatal(err)
+	}
+
+The demo directory holds the interactive program that used to live here,
+for trying out a new backend by hand.
+*/
+package wdetest
 
 import (
-	"fmt"
+	"errors"
 	"github.com/skelterjohn/go.wde"
-	_ "github.com/skelterjohn/go.wde/init"
-	"image/color"
-	"math/rand"
-	"runtime"
+	"image"
+	"image/draw"
 	"sync"
 	"time"
 )
 
-func main() {
-	go wdetest()
-	wde.Run()
+var ErrTimeout = errors.New("wdetest: timed out waiting for FlushImage")
+
+// Window wraps a wde.Window so that a test can inject events into its event
+// channel and observe its calls to FlushImage. Every other method goes
+// straight to the wrapped window.
+type Window struct {
+	wde.Window
+
+	lock    sync.Mutex
+	flushes int
+	frame   *image.RGBA
+	flushed chan struct{}
+
+	sendLock sync.RWMutex
+	done     chan struct{}
+	events   chan wde.Event
+}
+
+func Wrap(w wde.Window) (tw *Window) {
+	tw = &Window{
+		Window:  w,
+		flushed: make(chan struct{}),
+		done:    make(chan struct{}),
+		events:  make(chan wde.Event),
+	}
+	go tw.forward(w.EventChan())
+	return
+}
+
+func (w *Window) forward(events <-chan wde.Event) {
+	for e := range events {
+		w.Inject(e)
+	}
+	// release any Inject still waiting before waiting for them to finish
+	close(w.done)
+	w.sendLock.Lock()
+	close(w.events)
+	w.sendLock.Unlock()
+}
 
-	println("done")
+// Inject delivers e to the application, interleaved with whatever the
+// wrapped window itself produces. Its Window method will report the
+// wrapper, as will those of the wrapped window's own events. Inject returns
+// once the application has received e, or straight away if the wrapped
+// window's channel has closed.
+func (w *Window) Inject(e wde.Event) {
+	if e = wde.WithWindow(e, w); e == nil {
+		return
+	}
+	w.sendLock.RLock()
+	defer w.sendLock.RUnlock()
+	select {
+	case <-w.done:
+		return
+	default:
+	}
+	select {
+	case w.events <- e:
+	case <-w.done:
+	}
 }
 
-func wdetest() {
-	var wg sync.WaitGroup
+// Play injects each event in turn.
+func (w *Window) Play(events ...wde.Event) {
+	for _, e := range events {
+		w.Inject(e)
+	}
+}
 
-	size := 200
+func (w *Window) EventChan() (events <-chan wde.Event) {
+	return w.events
+}
+
+// FlushImage flushes the wrapped window and keeps a copy of what was on its
+// screen, for Frame to return.
+func (w *Window) FlushImage(bounds ...image.Rectangle) {
+	w.Window.FlushImage(bounds...)
+
+	s := w.Window.Screen()
+	frame := image.NewRGBA(s.Bounds())
+	draw.Draw(frame, frame.Bounds(), s, frame.Bounds().Min, draw.Src)
+
+	w.lock.Lock()
+	w.flushes++
+	w.frame = frame
+	close(w.flushed)
+	w.flushed = make(chan struct{})
+	w.lock.Unlock()
+}
 
-	x := func() {
-		offset := time.Duration(rand.Intn(1e9))
+// FlushCount returns the number of times the application has called
+// FlushImage.
+func (w *Window) FlushCount() int {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.flushes
+}
 
-		dw, err := wde.NewWindow(size, size)
-		if err != nil {
-			fmt.Println(err)
+// WaitFlush waits until FlushImage has been called more than n times in
+// total. Take n from FlushCount before injecting the events the application
+// should respond to, so that a flush racing ahead of WaitFlush is not missed.
+func (w *Window) WaitFlush(n int, timeout time.Duration) (err error) {
+	deadline := time.After(timeout)
+	for {
+		w.lock.Lock()
+		count, flushed := w.flushes, w.flushed
+		w.lock.Unlock()
+		if count > n {
 			return
 		}
-		dw.SetTitle("hi!")
-		dw.SetSize(size, size)
-		dw.Show()
-
-		events := dw.EventChan()
-
-		done := make(chan bool)
-
-		go func() {
-		loop:
-			for ei := range events {
-				runtime.Gosched()
-				switch e := ei.(type) {
-				case wde.MouseDownEvent:
-					fmt.Println("clicked", e.Where.X, e.Where.Y, e.Which)
-					// dw.Close()
-					// break loop
-				case wde.MouseUpEvent:
-				case wde.MouseMovedEvent:
-				case wde.MouseDraggedEvent:
-				case wde.MouseEnteredEvent:
-					fmt.Println("mouse entered", e.Where.X, e.Where.Y)
-				case wde.MouseExitedEvent:
-					fmt.Println("mouse exited", e.Where.X, e.Where.Y)
-				case wde.KeyDownEvent:
-					// fmt.Println("KeyDownEvent", e.Glyph)
-				case wde.KeyUpEvent:
-					// fmt.Println("KeyUpEvent", e.Glyph)
-				case wde.KeyTypedEvent:
-					fmt.Println("typed", e.Key, e.Glyph, e.Chord)
-				case wde.CloseEvent:
-					fmt.Println("close")
-					dw.Close()
-					break loop
-				case wde.ResizeEvent:
-					fmt.Println("resize", e.Width, e.Height)
-				}
-			}
-			done <- true
-			fmt.Println("end of events")
-		}()
-
-		for i := 0; ; i++ {
-			width, height := dw.Size()
-			s := dw.Screen()
-			for x := 0; x < width; x++ {
-				for y := 0; y < height; y++ {
-					s.Set(x, y, color.White)
-				}
-			}
-			for x := 0; x < width; x++ {
-				for y := 0; y < height; y++ {
-					var r uint8
-					if x > width/2 {
-						r = 255
-					}
-					var g uint8
-					if y >= height/2 {
-						g = 255
-					}
-					var b uint8
-					if y < height/4 || y >= height*3/4 {
-						b = 255
-					}
-					if i%2 == 1 {
-						r = 255 - r
-					}
-
-					if y > height-10 {
-						r = 255
-						g = 255
-						b = 255
-					}
-
-					if x == y {
-						r = 100
-						g = 100
-						b = 100
-					}
-
-					s.Set(x, y, color.RGBA{r, g, b, 255})
-				}
-			}
-			dw.FlushImage()
-			select {
-			case <-time.After(5e8 + offset):
-			case <-done:
-				wg.Done()
-				return
-			}
+		select {
+		case <-flushed:
+		case <-deadline:
+			return ErrTimeout
 		}
 	}
-	wg.Add(1)
-	go x()
-	wg.Add(1)
-	go x()
+}
+
+// Step plays events and then waits for the application to flush in
+// response.
+func (w *Window) Step(timeout time.Duration, events ...wde.Event) (err error) {
+	n := w.FlushCount()
+	w.Play(events...)
+	return w.WaitFlush(n, timeout)
+}
 
-	wg.Wait()
-	wde.Stop()
+// Frame returns a copy of the screen as it was at the most recent
+// FlushImage, or nil if there has been none.
+func (w *Window) Frame() *image.RGBA {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.frame
 }
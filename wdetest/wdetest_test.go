@@ -0,0 +1,68 @@
+/*
+   Copyright 2012 the go.wde authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package wdetest
+
+import (
+	"github.com/skelterjohn/go.wde"
+	"github.com/skelterjohn/go.wde/headless"
+	"testing"
+	"time"
+)
+
+// Closing the wrapped window must release an Inject that is waiting for the
+// application, which here never reads.
+func TestCloseReleasesInject(t *testing.T) {
+	hw, err := headless.NewWindow(10, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := Wrap(hw)
+
+	injected := make(chan struct{})
+	go func() {
+		w.Inject(wde.FocusEvent{})
+		close(injected)
+	}()
+	// give Inject time to block on the unread channel
+	time.Sleep(10 * time.Millisecond)
+	hw.Close()
+
+	select {
+	case <-injected:
+	case <-time.After(time.Second):
+		t.Fatal("Inject still blocked after the wrapped window closed")
+	}
+	// the event may or may not have made it before the close
+	for range w.EventChan() {
+	}
+}
+
+// An Inject after the wrapped window has closed must return, not send on the
+// closed event channel.
+func TestInjectAfterClose(t *testing.T) {
+	hw, err := headless.NewWindow(10, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := Wrap(hw)
+	hw.Close()
+	for range w.EventChan() {
+	}
+	for i := 0; i < 100; i++ {
+		w.Inject(wde.FocusEvent{})
+	}
+}
@@ -21,6 +21,8 @@ import (
 	"github.com/AllenDang/w32"
 	"github.com/skelterjohn/go.wde"
 	"image"
+	"time"
+	"unicode/utf16"
 	"unsafe"
 )
 
@@ -29,12 +31,57 @@ type EventData struct {
 	button       wde.Button
 	noX          int
 	trackMouse   bool
+	clock        wde.MillisecondClock
+	clicks       wde.ClickCounter
+	// highSurrogate holds the first half of a character that WM_CHAR is
+	// delivering in two messages
+	highSurrogate uint16
+	// state is the WindowState last reported
+	state wde.WindowState
+	// downKeys holds the keys pressed in this window, for chords
+	downKeys map[string]bool
 }
 
 func (this *EventData) InitEventData() {
 	this.noX = 1<<31 - 1
 	this.noX++
 	this.lastX = this.noX
+	this.downKeys = make(map[string]bool)
+}
+
+const (
+	WM_MOUSEHWHEEL = 0x020E
+	WHEEL_DELTA    = 120
+
+	WM_IME_ENDCOMPOSITION = 0x010E
+	WM_IME_COMPOSITION    = 0x010F
+	GCS_COMPSTR           = 0x0008
+	GCS_CURSORPOS         = 0x0080
+
+	WM_DPICHANGED = 0x02E0
+
+	WM_GETMINMAXINFO = 0x0024
+
+	// WM_WDE_SETFULLSCREEN and WM_WDE_SETSTATE are posted to have the
+	// window's thread change the window's state, so that the goroutine
+	// reading events never waits for a thread that may be waiting for it.
+	// WM_WDE_SETFULLSCREEN has wparam 1 to go fullscreen and 0 to come
+	// back; WM_WDE_SETSTATE has the wde.WindowState to go to.
+	WM_WDE_SETFULLSCREEN = 0x0400 // WM_USER
+	WM_WDE_SETSTATE      = 0x0401
+
+	SIZE_RESTORED  = 0
+	SIZE_MINIMIZED = 1
+	SIZE_MAXIMIZED = 2
+)
+
+// minMaxInfo is the MINMAXINFO that WM_GETMINMAXINFO points to.
+type minMaxInfo struct {
+	ptReserved     w32.POINT
+	ptMaxSize      w32.POINT
+	ptMaxPosition  w32.POINT
+	ptMinTrackSize w32.POINT
+	ptMaxTrackSize w32.POINT
 }
 
 func buttonForDetail(button uint32) wde.Button {
@@ -49,20 +96,51 @@ func buttonForDetail(button uint32) wde.Button {
 	return 0
 }
 
+func currentModifiers() (mods wde.Modifiers) {
+	if GetKeyState(w32.VK_SHIFT)&0x8000 != 0 {
+		mods |= wde.ModShift
+	}
+	if GetKeyState(w32.VK_CONTROL)&0x8000 != 0 {
+		mods |= wde.ModControl
+	}
+	if GetKeyState(w32.VK_MENU)&0x8000 != 0 {
+		mods |= wde.ModAlt
+	}
+	if GetKeyState(w32.VK_LWIN)&0x8000 != 0 || GetKeyState(w32.VK_RWIN)&0x8000 != 0 {
+		mods |= wde.ModSuper
+	}
+	if GetKeyState(w32.VK_CAPITAL)&1 != 0 {
+		mods |= wde.ModCapsLock
+	}
+	if GetKeyState(w32.VK_NUMLOCK)&1 != 0 {
+		mods |= wde.ModNumLock
+	}
+	return
+}
+
 func WndProc(hwnd w32.HWND, msg uint32, wparam, lparam uintptr) uintptr {
 	wnd := GetMsgHandler(hwnd)
 	if wnd == nil {
 		return uintptr(w32.DefWindowProc(hwnd, msg, wparam, lparam))
 	}
 
+	when := wnd.clock.Stamp(GetMessageTime())
+
 	var rc uintptr
 	switch msg {
 	case w32.WM_LBUTTONDOWN, w32.WM_RBUTTONDOWN, w32.WM_MBUTTONDOWN:
 		wnd.button = wnd.button | buttonForDetail(msg)
 		var bpe wde.MouseDownEvent
+		bpe.Source = wnd
+		bpe.When = when
+		bpe.Mods = currentModifiers()
 		bpe.Which = buttonForDetail(msg)
 		bpe.Where.X = int(lparam) & 0xFFFF
 		bpe.Where.Y = int(lparam>>16) & 0xFFFF
+		// the user can change these at any time in the control panel
+		wnd.clicks.Interval = time.Duration(GetDoubleClickTime()) * time.Millisecond
+		wnd.clicks.Distance = w32.GetSystemMetrics(w32.SM_CXDOUBLECLK) / 2
+		bpe.Clicks = wnd.clicks.Press(bpe.Which, bpe.Where, bpe.When)
 		wnd.lastX = bpe.Where.X
 		wnd.lastY = bpe.Where.Y
 		wnd.events <- bpe
@@ -70,35 +148,40 @@ func WndProc(hwnd w32.HWND, msg uint32, wparam, lparam uintptr) uintptr {
 	case w32.WM_LBUTTONUP, w32.WM_RBUTTONUP, w32.WM_MBUTTONUP:
 		wnd.button = wnd.button & ^buttonForDetail(msg)
 		var bpe wde.MouseUpEvent
+		bpe.Source = wnd
+		bpe.When = when
+		bpe.Mods = currentModifiers()
 		bpe.Which = buttonForDetail(msg)
 		bpe.Where.X = int(lparam) & 0xFFFF
 		bpe.Where.Y = int(lparam>>16) & 0xFFFF
+		bpe.Clicks = wnd.clicks.Release(bpe.Which)
 		wnd.lastX = bpe.Where.X
 		wnd.lastY = bpe.Where.Y
 		wnd.events <- bpe
 
-	case w32.WM_MOUSEWHEEL:
-		var mde wde.MouseDownEvent
-		var mue wde.MouseUpEvent
-		mde.Where.X = int(lparam) & 0xFFFF
-		mde.Where.Y = int(lparam>>16) & 0xFFFF
-		mue.Where.X = int(lparam) & 0xFFFF
-		mue.Where.Y = int(lparam>>16) & 0xFFFF
+	case w32.WM_MOUSEWHEEL, WM_MOUSEHWHEEL:
+		var se wde.ScrollEvent
+		se.Source = wnd
+		se.When = when
+		se.Mods = currentModifiers()
+		// wheel messages carry screen coordinates
+		x, y, _ := w32.ScreenToClient(hwnd, int(int16(lparam&0xFFFF)), int(int16((lparam>>16)&0xFFFF)))
+		se.Where.X = x
+		se.Where.Y = y
 		delta := int16((wparam >> 16) & 0xFFFF)
-		if delta > 0 {
-			mde.Which = wde.WheelUpButton
-			mue.Which = wde.WheelUpButton
+		if msg == w32.WM_MOUSEWHEEL {
+			se.DeltaY = float64(delta) / WHEEL_DELTA
 		} else {
-			mde.Which = wde.WheelDownButton
-			mue.Which = wde.WheelDownButton
+			se.DeltaX = float64(delta) / WHEEL_DELTA
 		}
-		wnd.lastX = mde.Where.X
-		wnd.lastX = mde.Where.Y
-		wnd.events <- mde
-		wnd.events <- mue
+		se.Precise = delta%WHEEL_DELTA != 0
+		wnd.events <- se
 
 	case w32.WM_MOUSEMOVE:
 		var mme wde.MouseMovedEvent
+		mme.Source = wnd
+		mme.When = when
+		mme.Mods = currentModifiers()
 		mme.Where.X = int(lparam) & 0xFFFF
 		mme.Where.Y = int(lparam>>16) & 0xFFFF
 		if wnd.lastX != wnd.noX {
@@ -135,46 +218,213 @@ func WndProc(hwnd w32.HWND, msg uint32, wparam, lparam uintptr) uintptr {
 		wnd.trackMouse = false
 
 		var wee wde.MouseExitedEvent
+		wee.Source = wnd
+		wee.When = when
+		wee.Mods = currentModifiers()
 		// TODO: get real position
 		wee.Where.Y = wnd.lastX
 		wee.Where.X = wnd.lastY
 		wnd.events <- wee
 
-	case w32.WM_KEYDOWN:
+	case w32.WM_KEYDOWN, w32.WM_SYSKEYDOWN:
 		// TODO: letter
 		key, exists := codeKeys[wparam]
 		if !exists {
 			key = fmt.Sprintf("%d", wparam)
 		}
-		ke := wde.KeyEvent{key}
+		var ke wde.KeyEvent
+		ke.Source = wnd
+		ke.When = when
+		ke.Mods = currentModifiers()
+		ke.Key = key
+		ke.Scancode = scancodeForLparam(lparam)
 
-		wnd.events <- wde.KeyDownEvent(ke)
+		// bit 30 of lparam says the key was already down
+		repeat := lparam&(1<<30) != 0
+		wnd.events <- wde.KeyDownEvent{KeyEvent: ke, Repeat: repeat}
+		wnd.downKeys[key] = true
 		kpe := wde.KeyTypedEvent{
 			KeyEvent: ke,
+			Repeat:   repeat,
+			Chord:    wde.ConstructChord(wnd.downKeys),
 		}
 		wnd.events <- kpe
+		if msg == w32.WM_SYSKEYDOWN {
+			// Alt+F4 and the window menu still need the default handling
+			rc = w32.DefWindowProc(hwnd, msg, wparam, lparam)
+		}
 
-	case w32.WM_KEYUP:
+	case w32.WM_KEYUP, w32.WM_SYSKEYUP:
 		// TODO: letter
 		key, exists := codeKeys[wparam]
 		if !exists {
 			key = fmt.Sprintf("%d", wparam)
 		}
-		wnd.events <- wde.KeyUpEvent{key}
+		var ke wde.KeyUpEvent
+		ke.Source = wnd
+		ke.When = when
+		ke.Mods = currentModifiers()
+		ke.Key = key
+		ke.Scancode = scancodeForLparam(lparam)
+		delete(wnd.downKeys, key)
+		wnd.events <- ke
+		if msg == w32.WM_SYSKEYUP {
+			rc = w32.DefWindowProc(hwnd, msg, wparam, lparam)
+		}
+
+	case w32.WM_CHAR:
+		r := rune(wparam)
+		if utf16.IsSurrogate(r) && r < 0xdc00 {
+			// the rest of the character comes with the next WM_CHAR
+			wnd.highSurrogate = uint16(r)
+			break
+		}
+		if utf16.IsSurrogate(r) {
+			r = utf16.DecodeRune(rune(wnd.highSurrogate), r)
+			wnd.highSurrogate = 0
+		}
+		// control characters come from Control chords, not typing
+		if r < 0x20 || r == 0x7f {
+			break
+		}
+		var te wde.TextInputEvent
+		te.Source = wnd
+		te.When = when
+		te.Mods = currentModifiers()
+		te.Text = string(r)
+		wnd.events <- te
+
+	case WM_IME_COMPOSITION:
+		if lparam&GCS_COMPSTR != 0 {
+			text, cursor := GetCompositionString(hwnd)
+			var ce wde.TextCompositionEvent
+			ce.Source = wnd
+			ce.When = when
+			ce.Mods = currentModifiers()
+			ce.Text = string(utf16.Decode(text))
+			ce.Cursor = len(utf16.Decode(text[:cursor]))
+			wnd.events <- ce
+		}
+		// the committed text comes back as WM_CHAR
+		rc = w32.DefWindowProc(hwnd, msg, wparam, lparam)
+
+	case WM_IME_ENDCOMPOSITION:
+		var ce wde.TextCompositionEvent
+		ce.Source = wnd
+		ce.When = when
+		ce.Mods = currentModifiers()
+		wnd.events <- ce
+		rc = w32.DefWindowProc(hwnd, msg, wparam, lparam)
+
+	case w32.WM_SETFOCUS, w32.WM_KILLFOCUS:
+		var fe wde.FocusEvent
+		fe.Source = wnd
+		fe.When = when
+		fe.Gained = msg == w32.WM_SETFOCUS
+		if !fe.Gained {
+			// key releases go to whichever window has the focus now, so
+			// forget what was held rather than leave it stuck in chords
+			wnd.downKeys = make(map[string]bool)
+		}
+		wnd.events <- fe
+		rc = w32.DefWindowProc(hwnd, msg, wparam, lparam)
+
+	case WM_WDE_SETFULLSCREEN:
+		wnd.setFullscreen(wparam != 0)
+
+	case WM_WDE_SETSTATE:
+		wnd.setState(wde.WindowState(wparam))
 
 	case w32.WM_SIZE:
-		width := int(lparam) & 0xFFFF
-		height := int(lparam>>16) & 0xFFFF
-		wnd.buffer = NewDIB(image.Rect(0, 0, width, height))
-		wnd.events <- wde.ResizeEvent{width, height}
+		state := wde.StateNormal
+		switch {
+		case wparam == SIZE_MINIMIZED:
+			state = wde.StateMinimized
+		case wparam == SIZE_MAXIMIZED:
+			state = wde.StateMaximized
+		case wnd.fullscreen:
+			state = wde.StateFullscreen
+		}
+		// a minimized window keeps its size, though it is told it has none
+		if state != wde.StateMinimized {
+			width := int(lparam) & 0xFFFF
+			height := int(lparam>>16) & 0xFFFF
+			wnd.buffer = NewDIB(image.Rect(0, 0, width, height))
+			var re wde.ResizeEvent
+			re.Source = wnd
+			re.When = when
+			re.Width, re.Height = width, height
+			wnd.events <- re
+		}
+		if state != wnd.state {
+			wnd.state = state
+			var se wde.WindowStateEvent
+			se.Source = wnd
+			se.When = when
+			se.State = state
+			wnd.events <- se
+		}
 		rc = w32.DefWindowProc(hwnd, msg, wparam, lparam)
 
-	case w32.WM_PAINT:
-		wnd.Repaint()
+	case w32.WM_MOVE:
+		// lparam holds where the client area went; report the frame
+		var me wde.MoveEvent
+		me.Source = wnd
+		me.When = when
+		me.X, me.Y = wnd.Position()
+		wnd.events <- me
 		rc = w32.DefWindowProc(hwnd, msg, wparam, lparam)
 
+	case WM_GETMINMAXINFO:
+		// Windows fills in its defaults, which the window's limits narrow
+		mmi := (*minMaxInfo)(unsafe.Pointer(lparam))
+		if wnd.minTrack.X > 0 {
+			mmi.ptMinTrackSize.X = int32(wnd.minTrack.X)
+		}
+		if wnd.minTrack.Y > 0 {
+			mmi.ptMinTrackSize.Y = int32(wnd.minTrack.Y)
+		}
+		if wnd.maxTrack.X > 0 {
+			mmi.ptMaxTrackSize.X = int32(wnd.maxTrack.X)
+		}
+		if wnd.maxTrack.Y > 0 {
+			mmi.ptMaxTrackSize.Y = int32(wnd.maxTrack.Y)
+		}
+
+	case WM_DPICHANGED:
+		// only DPI aware programs get this, with the rectangle Windows
+		// suggests the window take at the new DPI
+		suggested := (*w32.RECT)(unsafe.Pointer(lparam))
+		w32.SetWindowPos(hwnd, 0,
+			int(suggested.Left), int(suggested.Top),
+			int(suggested.Right-suggested.Left), int(suggested.Bottom-suggested.Top),
+			w32.SWP_NOZORDER|w32.SWP_NOACTIVATE)
+		var se wde.ScaleChangedEvent
+		se.Source = wnd
+		se.When = when
+		se.Scale = scaleForDPI(float64(wparam & 0xFFFF))
+		wnd.events <- se
+
+	case w32.WM_PAINT:
+		var ps w32.PAINTSTRUCT
+		hdc := w32.BeginPaint(hwnd, &ps)
+		wnd.blitImage(hdc, wnd.bufferback, wnd.bufferback.Bounds())
+		w32.EndPaint(hwnd, &ps)
+
+		var ee wde.ExposeEvent
+		ee.Source = wnd
+		ee.When = when
+		ee.Rects = []image.Rectangle{image.Rect(
+			int(ps.RcPaint.Left), int(ps.RcPaint.Top),
+			int(ps.RcPaint.Right), int(ps.RcPaint.Bottom),
+		)}
+		wnd.events <- ee
+
 	case w32.WM_CLOSE:
-		wnd.events <- wde.CloseEvent{}
+		var ce wde.CloseEvent
+		ce.Source = wnd
+		ce.When = when
+		wnd.events <- ce
 
 	case w32.WM_DESTROY:
 		w32.PostQuitMessage(0)
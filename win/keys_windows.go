@@ -26,24 +26,10 @@ var codeKeys map[uintptr]string
 TODO:
  F10 loses focus
  left alt loses focus
-<	(left from z), code 226. Coded as ',' in xgb for some reason.
 */
 
 func init() {
 	codeKeys = map[uintptr]string{
-	
-		// Some that are not found in wde constants
-		// Hardcoded to be compatible with xgb
-		19:	"Pause",
-		93:	"Menu",	
-		145:	"Scroll_Lock",
-		186:	"dead_diaeresis",	// ¨
-		192:	"odiaresis",			// ö
-		220:	"section",				// §
-		221:	"aring",					// å
-		222:	"adiaresis",			// ä
-
-	
 		'A':	wde.KeyA,
 		'B':	wde.KeyB,
 		'C':	wde.KeyC,
@@ -76,6 +62,7 @@ func init() {
 		16:	wde.KeyLeftShift,	// Right sends same key
 		17:	wde.KeyLeftControl,	// Right sends same key
 		18:	wde.KeyRightAlt,	//17 and 18 at the same time.
+		19:	wde.KeyPause,
 		20:	wde.KeyCapsLock,
 		27:	wde.KeyEscape,
 		32:	wde.KeySpace,
@@ -87,6 +74,7 @@ func init() {
 		38:	wde.KeyUpArrow,
 		39:	wde.KeyRightArrow,
 		40:	wde.KeyDownArrow,
+		44:	wde.KeyPrintScreen,
 		45:	wde.KeyInsert,
 		46:	wde.KeyDelete,
 		48:	wde.Key0,
@@ -101,18 +89,20 @@ func init() {
 		57:	wde.Key9,
 		91:	wde.KeyLeftSuper,	// left windows key
 		92:	wde.KeyRightSuper,	// right windows key
-		96:	wde.KeyPadInsert,
-		97:	wde.KeyPadEnd,
-		98:	wde.KeyPadDown,
-		99:	wde.KeyPadNext,
-		100:	wde.KeyPadLeft,
-		101:	wde.KeyPadBegin,
-		102:	wde.KeyPadRight,
-		103:	wde.KeyPadHome,
-		104:	wde.KeyPadUp,
-		105:	wde.KeyPadPrior,
+		93:	wde.KeyMenu,
+		96:	wde.KeyPad0,
+		97:	wde.KeyPad1,
+		98:	wde.KeyPad2,
+		99:	wde.KeyPad3,
+		100:	wde.KeyPad4,
+		101:	wde.KeyPad5,
+		102:	wde.KeyPad6,
+		103:	wde.KeyPad7,
+		104:	wde.KeyPad8,
+		105:	wde.KeyPad9,
 		106:	wde.KeyPadStar,
 		107:	wde.KeyPadPlus,
+		108:	wde.KeyPadComma,
 		109:	wde.KeyPadMinus,
 		110:	wde.KeyPadDot,
 		111:	wde.KeyPadSlash,
@@ -128,12 +118,175 @@ func init() {
 		121:	wde.KeyF10,	// loses focus
 		122:	wde.KeyF11,
 		123:	wde.KeyF12,
+		124:	wde.KeyF13,
+		125:	wde.KeyF14,
+		126:	wde.KeyF15,
+		127:	wde.KeyF16,
+		128:	wde.KeyF17,
+		129:	wde.KeyF18,
+		130:	wde.KeyF19,
+		131:	wde.KeyF20,
+		132:	wde.KeyF21,
+		133:	wde.KeyF22,
+		134:	wde.KeyF23,
+		135:	wde.KeyF24,
 		144:	wde.KeyNumlock,
-		187:	wde.KeyMinus,
+		145:	wde.KeyScrollLock,
+		173:	wde.KeyMute,
+		174:	wde.KeyVolumeDown,
+		175:	wde.KeyVolumeUp,
+		176:	wde.KeyMediaNext,
+		177:	wde.KeyMediaPrev,
+		178:	wde.KeyMediaStop,
+		179:	wde.KeyMediaPlay,
+		// The OEM keys are named after what they type on a US keyboard.
+		186:	wde.KeySemicolon,
+		187:	wde.KeyEqual,
 		188:	wde.KeyComma,
-		189:	wde.KeySlash,
+		189:	wde.KeyMinus,
 		190:	wde.KeyPeriod,
-		191:	wde.KeyBackslash,
-		219:	wde.KeyEqual,
+		191:	wde.KeySlash,
+		192:	wde.KeyBackTick,
+		219:	wde.KeyLeftBracket,
+		220:	wde.KeyBackslash,
+		221:	wde.KeyRightBracket,
+		222:	wde.KeyQuote,
+		226:	wde.KeyIntlBackslash,
+	}
+}
+
+// scancodes maps the scan codes of WM_KEYDOWN and WM_KEYUP, with 0xe000
+// added for extended keys, to where the keys are.
+var scancodes = map[uint32]wde.Scancode{
+	0x0001: wde.ScancodeEscape,
+	0x0002: wde.Scancode1,
+	0x0003: wde.Scancode2,
+	0x0004: wde.Scancode3,
+	0x0005: wde.Scancode4,
+	0x0006: wde.Scancode5,
+	0x0007: wde.Scancode6,
+	0x0008: wde.Scancode7,
+	0x0009: wde.Scancode8,
+	0x000a: wde.Scancode9,
+	0x000b: wde.Scancode0,
+	0x000c: wde.ScancodeMinus,
+	0x000d: wde.ScancodeEqual,
+	0x000e: wde.ScancodeBackspace,
+	0x000f: wde.ScancodeTab,
+	0x0010: wde.ScancodeQ,
+	0x0011: wde.ScancodeW,
+	0x0012: wde.ScancodeE,
+	0x0013: wde.ScancodeR,
+	0x0014: wde.ScancodeT,
+	0x0015: wde.ScancodeY,
+	0x0016: wde.ScancodeU,
+	0x0017: wde.ScancodeI,
+	0x0018: wde.ScancodeO,
+	0x0019: wde.ScancodeP,
+	0x001a: wde.ScancodeLeftBracket,
+	0x001b: wde.ScancodeRightBracket,
+	0x001c: wde.ScancodeReturn,
+	0x001d: wde.ScancodeLeftControl,
+	0x001e: wde.ScancodeA,
+	0x001f: wde.ScancodeS,
+	0x0020: wde.ScancodeD,
+	0x0021: wde.ScancodeF,
+	0x0022: wde.ScancodeG,
+	0x0023: wde.ScancodeH,
+	0x0024: wde.ScancodeJ,
+	0x0025: wde.ScancodeK,
+	0x0026: wde.ScancodeL,
+	0x0027: wde.ScancodeSemicolon,
+	0x0028: wde.ScancodeQuote,
+	0x0029: wde.ScancodeBackTick,
+	0x002a: wde.ScancodeLeftShift,
+	0x002b: wde.ScancodeBackslash,
+	0x002c: wde.ScancodeZ,
+	0x002d: wde.ScancodeX,
+	0x002e: wde.ScancodeC,
+	0x002f: wde.ScancodeV,
+	0x0030: wde.ScancodeB,
+	0x0031: wde.ScancodeN,
+	0x0032: wde.ScancodeM,
+	0x0033: wde.ScancodeComma,
+	0x0034: wde.ScancodePeriod,
+	0x0035: wde.ScancodeSlash,
+	0x0036: wde.ScancodeRightShift,
+	0x0037: wde.ScancodePadStar,
+	0x0038: wde.ScancodeLeftAlt,
+	0x0039: wde.ScancodeSpace,
+	0x003a: wde.ScancodeCapsLock,
+	0x003b: wde.ScancodeF1,
+	0x003c: wde.ScancodeF2,
+	0x003d: wde.ScancodeF3,
+	0x003e: wde.ScancodeF4,
+	0x003f: wde.ScancodeF5,
+	0x0040: wde.ScancodeF6,
+	0x0041: wde.ScancodeF7,
+	0x0042: wde.ScancodeF8,
+	0x0043: wde.ScancodeF9,
+	0x0044: wde.ScancodeF10,
+	0x0045: wde.ScancodePause,
+	0x0046: wde.ScancodeScrollLock,
+	0x0047: wde.ScancodePad7,
+	0x0048: wde.ScancodePad8,
+	0x0049: wde.ScancodePad9,
+	0x004a: wde.ScancodePadMinus,
+	0x004b: wde.ScancodePad4,
+	0x004c: wde.ScancodePad5,
+	0x004d: wde.ScancodePad6,
+	0x004e: wde.ScancodePadPlus,
+	0x004f: wde.ScancodePad1,
+	0x0050: wde.ScancodePad2,
+	0x0051: wde.ScancodePad3,
+	0x0052: wde.ScancodePad0,
+	0x0053: wde.ScancodePadDot,
+	0x0056: wde.ScancodeNonUSBackslash,
+	0x0057: wde.ScancodeF11,
+	0x0058: wde.ScancodeF12,
+	0x0059: wde.ScancodePadEqual,
+	0x0064: wde.ScancodeF13,
+	0x0065: wde.ScancodeF14,
+	0x0066: wde.ScancodeF15,
+	0x0067: wde.ScancodeF16,
+	0x0068: wde.ScancodeF17,
+	0x0069: wde.ScancodeF18,
+	0x006a: wde.ScancodeF19,
+	0x006b: wde.ScancodeF20,
+	0x006c: wde.ScancodeF21,
+	0x006d: wde.ScancodeF22,
+	0x006e: wde.ScancodeF23,
+	0x0076: wde.ScancodeF24,
+	0xe01c: wde.ScancodePadEnter,
+	0xe01d: wde.ScancodeRightControl,
+	0xe020: wde.ScancodeMute,
+	0xe02e: wde.ScancodeVolumeDown,
+	0xe030: wde.ScancodeVolumeUp,
+	0xe035: wde.ScancodePadSlash,
+	0xe037: wde.ScancodePrintScreen,
+	0xe038: wde.ScancodeRightAlt,
+	0xe045: wde.ScancodeNumlock,
+	0xe047: wde.ScancodeHome,
+	0xe048: wde.ScancodeUpArrow,
+	0xe049: wde.ScancodePrior,
+	0xe04b: wde.ScancodeLeftArrow,
+	0xe04d: wde.ScancodeRightArrow,
+	0xe04f: wde.ScancodeEnd,
+	0xe050: wde.ScancodeDownArrow,
+	0xe051: wde.ScancodeNext,
+	0xe052: wde.ScancodeInsert,
+	0xe053: wde.ScancodeDelete,
+	0xe05b: wde.ScancodeLeftSuper,
+	0xe05c: wde.ScancodeRightSuper,
+	0xe05d: wde.ScancodeMenu,
+}
+
+// scancodeForLparam returns the physical key of a WM_KEYDOWN or WM_KEYUP
+// message.
+func scancodeForLparam(lparam uintptr) wde.Scancode {
+	code := uint32(lparam>>16) & 0xff
+	if lparam&(1<<24) != 0 {
+		code |= 0xe000
 	}
+	return scancodes[code]
 }
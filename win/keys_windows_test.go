@@ -0,0 +1,83 @@
+/*
+   Copyright 2012 the go.wde authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package win
+
+import (
+	"github.com/skelterjohn/go.wde"
+	"testing"
+)
+
+func TestScancodeForLparam(t *testing.T) {
+	for _, test := range []struct {
+		code     uintptr
+		extended bool
+		want     wde.Scancode
+	}{
+		{0x01, false, wde.ScancodeEscape},
+		{0x02, false, wde.Scancode1},
+		{0x0b, false, wde.Scancode0},
+		{0x10, false, wde.ScancodeQ},
+		{0x1c, false, wde.ScancodeReturn},
+		{0x1c, true, wde.ScancodePadEnter},
+		{0x1d, false, wde.ScancodeLeftControl},
+		{0x1d, true, wde.ScancodeRightControl},
+		{0x1e, false, wde.ScancodeA},
+		{0x38, false, wde.ScancodeLeftAlt},
+		{0x38, true, wde.ScancodeRightAlt},
+		{0x3b, false, wde.ScancodeF1},
+		{0x45, false, wde.ScancodePause},
+		{0x45, true, wde.ScancodeNumlock},
+		{0x47, false, wde.ScancodePad7},
+		{0x47, true, wde.ScancodeHome},
+		{0x48, true, wde.ScancodeUpArrow},
+		{0x53, false, wde.ScancodePadDot},
+		{0x53, true, wde.ScancodeDelete},
+		{0x56, false, wde.ScancodeNonUSBackslash},
+		{0x58, false, wde.ScancodeF12},
+		{0x5b, true, wde.ScancodeLeftSuper},
+		{0x5d, true, wde.ScancodeMenu},
+		{0x00, false, 0},
+	} {
+		// bits 16-23 hold the scan code, bit 24 the extended flag and
+		// bits 0-15 the repeat count
+		lparam := test.code<<16 | 1
+		if test.extended {
+			lparam |= 1 << 24
+		}
+		if got := scancodeForLparam(lparam); got != test.want {
+			t.Errorf("scancodeForLparam(%#x, extended=%v) = %#x, want %#x", test.code, test.extended, got, test.want)
+		}
+	}
+}
+
+func TestScancodesDistinct(t *testing.T) {
+	seen := make(map[wde.Scancode]uint32)
+	for code, sc := range scancodes {
+		if other, ok := seen[sc]; ok {
+			t.Errorf("scan codes %#x and %#x both map to %#x", other, code, sc)
+		}
+		seen[sc] = code
+	}
+}
+
+func TestCodeKeysKnown(t *testing.T) {
+	for code, key := range codeKeys {
+		if !wde.KnownKey(key) {
+			t.Errorf("codeKeys[%d] = %q, which is not a wde key", code, key)
+		}
+	}
+}
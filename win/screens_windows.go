@@ -0,0 +1,142 @@
+/*
+   Copyright 2012 the go.wde authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package win
+
+import (
+	"github.com/AllenDang/w32"
+	"github.com/skelterjohn/go.wde"
+	"image"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	procEnumDisplayMonitors = moduser32.NewProc("EnumDisplayMonitors")
+	procGetMonitorInfoW     = moduser32.NewProc("GetMonitorInfoW")
+	procGetDpiForWindow     = moduser32.NewProc("GetDpiForWindow")
+
+	modgdi32          = syscall.NewLazyDLL("gdi32.dll")
+	procCreateDCW     = modgdi32.NewProc("CreateDCW")
+	procDeleteDC      = modgdi32.NewProc("DeleteDC")
+	procGetDeviceCaps = modgdi32.NewProc("GetDeviceCaps")
+)
+
+const (
+	monitorInfoFPrimary = 1
+
+	capLogPixelsX = 88
+	capVRefresh   = 116
+)
+
+type monitorInfoEx struct {
+	CbSize    uint32
+	RcMonitor w32.RECT
+	RcWork    w32.RECT
+	DwFlags   uint32
+	SzDevice  [32]uint16
+}
+
+func rectangle(r w32.RECT) image.Rectangle {
+	return image.Rect(int(r.Left), int(r.Top), int(r.Right), int(r.Bottom))
+}
+
+var (
+	// enumMonitorProc is made once, as Go can only make so many callbacks
+	// and never frees them. enumLock guards enumScreens, which it fills.
+	enumMonitorProc = syscall.NewCallback(enumMonitor)
+	enumLock        sync.Mutex
+	enumScreens     []wde.Screen
+)
+
+// Screens lists the monitors that make up the desktop, the primary one
+// first.
+func Screens() (screens []wde.Screen, err error) {
+	enumLock.Lock()
+	defer enumLock.Unlock()
+	enumScreens = nil
+	procEnumDisplayMonitors.Call(0, 0, enumMonitorProc, 0)
+	screens, enumScreens = enumScreens, nil
+	return
+}
+
+// enumMonitor is the MONITORENUMPROC of Screens. It adds the monitor to
+// enumScreens.
+func enumMonitor(hmonitor, hdc uintptr, clip *w32.RECT, data uintptr) uintptr {
+	var mi monitorInfoEx
+	mi.CbSize = uint32(unsafe.Sizeof(mi))
+	if ok, _, _ := procGetMonitorInfoW.Call(hmonitor, uintptr(unsafe.Pointer(&mi))); ok == 0 {
+		return 1
+	}
+	s := wde.Screen{
+		Name:     syscall.UTF16ToString(mi.SzDevice[:]),
+		Bounds:   rectangle(mi.RcMonitor),
+		WorkArea: rectangle(mi.RcWork),
+		Primary:  mi.DwFlags&monitorInfoFPrimary != 0,
+	}
+	s.DPI, s.RefreshRate = deviceCaps(&mi.SzDevice[0])
+	s.Scale = scaleForDPI(s.DPI)
+	if s.Primary {
+		enumScreens = append([]wde.Screen{s}, enumScreens...)
+	} else {
+		enumScreens = append(enumScreens, s)
+	}
+	return 1
+}
+
+/*
+scaleForDPI returns the scale of a window at dpi. Windows reports 96 DPI,
+and stretches windows itself, unless the program declares in its manifest
+that it is DPI aware.
+*/
+func scaleForDPI(dpi float64) float64 {
+	if dpi <= 0 {
+		return 1
+	}
+	return dpi / 96
+}
+
+// windowDPI returns the DPI of the monitor hwnd is on, on Windows 10 and
+// later, and otherwise the system's DPI.
+func windowDPI(hwnd w32.HWND) float64 {
+	if procGetDpiForWindow.Find() == nil {
+		if dpi, _, _ := procGetDpiForWindow.Call(uintptr(hwnd)); dpi != 0 {
+			return float64(dpi)
+		}
+	}
+	dpi, _ := deviceCaps(nil)
+	return dpi
+}
+
+// deviceCaps asks the display device for its resolution and refresh rate.
+// A nil device means the whole desktop.
+func deviceCaps(device *uint16) (dpi, refresh float64) {
+	display := syscall.StringToUTF16Ptr("DISPLAY")
+	hdc, _, _ := procCreateDCW.Call(uintptr(unsafe.Pointer(display)), uintptr(unsafe.Pointer(device)), 0, 0)
+	if hdc == 0 {
+		return
+	}
+	defer procDeleteDC.Call(hdc)
+	px, _, _ := procGetDeviceCaps.Call(hdc, capLogPixelsX)
+	hz, _, _ := procGetDeviceCaps.Call(hdc, capVRefresh)
+	dpi = float64(int32(px))
+	// 0 and 1 mean the hardware's default rate, whatever it is
+	if int32(hz) > 1 {
+		refresh = float64(int32(hz))
+	}
+	return
+}
@@ -31,6 +31,21 @@ var (
 	gGeneralCallback uintptr
 )
 
+var (
+	moduser32          = syscall.NewLazyDLL("user32.dll")
+	procGetMessageTime = moduser32.NewProc("GetMessageTime")
+	procGetKeyState    = moduser32.NewProc("GetKeyState")
+
+	procGetDoubleClickTime = moduser32.NewProc("GetDoubleClickTime")
+	procIsZoomed           = moduser32.NewProc("IsZoomed")
+	procIsIconic           = moduser32.NewProc("IsIconic")
+
+	modimm32                     = syscall.NewLazyDLL("imm32.dll")
+	procImmGetContext            = modimm32.NewProc("ImmGetContext")
+	procImmReleaseContext        = modimm32.NewProc("ImmReleaseContext")
+	procImmGetCompositionStringW = modimm32.NewProc("ImmGetCompositionStringW")
+)
+
 func init() {
 	gWindows = make(map[w32.HWND]*Window)
 	gClasses = make([]string, 0)
@@ -57,7 +72,7 @@ func GetMsgHandler(hwnd w32.HWND) *Window {
 	return nil
 }
 
-func CreateWindow(className string, parent *Window, exStyle, style uint, width, height int) (w32.HWND, error) {
+func CreateWindow(className string, parent *Window, exStyle, style uint, x, y, width, height int) (w32.HWND, error) {
 	var parentHwnd w32.HWND
 	if parent != nil {
 		parentHwnd = parent.hwnd
@@ -68,8 +83,8 @@ func CreateWindow(className string, parent *Window, exStyle, style uint, width,
 		syscall.StringToUTF16Ptr(className),
 		nil,
 		style,
-		w32.CW_USEDEFAULT,
-		w32.CW_USEDEFAULT,
+		x,
+		y,
 		width,
 		height,
 		parentHwnd,
@@ -126,3 +141,59 @@ func RegClassOnlyOnce(className string) error {
 
 	return nil
 }
+
+// GetMessageTime returns the time, in milliseconds since the system
+// started, at which the message being handled was posted.
+func GetMessageTime() uint32 {
+	ret, _, _ := procGetMessageTime.Call()
+	return uint32(ret)
+}
+
+// GetDoubleClickTime returns the user's double-click interval, in
+// milliseconds.
+func GetDoubleClickTime() uint32 {
+	ret, _, _ := procGetDoubleClickTime.Call()
+	return uint32(ret)
+}
+
+// IsZoomed reports whether hwnd is maximized.
+func IsZoomed(hwnd w32.HWND) bool {
+	ret, _, _ := procIsZoomed.Call(uintptr(hwnd))
+	return ret != 0
+}
+
+// IsIconic reports whether hwnd is minimized.
+func IsIconic(hwnd w32.HWND) bool {
+	ret, _, _ := procIsIconic.Call(uintptr(hwnd))
+	return ret != 0
+}
+
+// GetKeyState returns the state of a virtual key as of the message being
+// handled: the high bit is set while the key is down, and the low bit while
+// a toggle key such as caps lock is on.
+func GetKeyState(vkey int) uint16 {
+	ret, _, _ := procGetKeyState.Call(uintptr(vkey))
+	return uint16(ret)
+}
+
+// GetCompositionString returns the text the input method is composing for
+// hwnd, and the position of the caret within it, in UTF-16 code units.
+func GetCompositionString(hwnd w32.HWND) (text []uint16, cursor int) {
+	himc, _, _ := procImmGetContext.Call(uintptr(hwnd))
+	if himc == 0 {
+		return
+	}
+	defer procImmReleaseContext.Call(uintptr(hwnd), himc)
+
+	n, _, _ := procImmGetCompositionStringW.Call(himc, GCS_COMPSTR, 0, 0)
+	if int32(n) > 0 {
+		text = make([]uint16, int32(n)/2)
+		procImmGetCompositionStringW.Call(himc, GCS_COMPSTR, uintptr(unsafe.Pointer(&text[0])), n)
+	}
+	c, _, _ := procImmGetCompositionStringW.Call(himc, GCS_CURSORPOS, 0, 0)
+	cursor = int(int32(c))
+	if cursor < 0 || cursor > len(text) {
+		cursor = len(text)
+	}
+	return
+}
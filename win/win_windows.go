@@ -30,6 +30,10 @@ func init() {
 		w, err = NewWindow(width, height)
 		return
 	}
+	wde.BackendNewWindowWithOptions = func(opts wde.WindowOptions) (w wde.Window, err error) {
+		w, err = NewWindowWithOptions(opts)
+		return
+	}
 	ch := make(chan struct{}, 1)
 	wde.BackendRun = func() {
 		<-ch
@@ -37,6 +41,7 @@ func init() {
 	wde.BackendStop = func() {
 		ch <- struct{}{}
 	}
+	wde.BackendScreens = Screens
 }
 
 const (
@@ -49,7 +54,20 @@ type Window struct {
 	hwnd       w32.HWND
 	buffer     *DIB
 	bufferback *DIB
-	events     chan interface{}
+	events     chan wde.Event
+
+	// minTrack and maxTrack limit the frame's size while the user resizes
+	// it, where not zero
+	minTrack, maxTrack image.Point
+	// showCmd is how the first Show shows the window
+	showCmd int
+
+	// fullscreen is set while the window covers its monitor, and
+	// restoreStyle and restoreRect are what to put back afterwards; only
+	// the window's thread uses them
+	fullscreen   bool
+	restoreStyle uintptr
+	restoreRect  w32.RECT
 }
 
 /*
@@ -65,49 +83,103 @@ go func(ready chan struct{}) {
 	<-ready
 */
 
-func makeTheWindow(width, height int) (w *Window, err error) {
+func makeTheWindow(opts wde.WindowOptions) (w *Window, err error) {
 
 	err = RegClassOnlyOnce(WIN_CLASSNAME)
 	if err != nil {
 		return
 	}
 
-	cr := &w32.RECT{
-		w32.CW_USEDEFAULT,
-		w32.CW_USEDEFAULT,
-		w32.CW_USEDEFAULT + int32(width),
-		w32.CW_USEDEFAULT + int32(height),
+	var exStyle, style uint = w32.WS_EX_CLIENTEDGE, w32.WS_OVERLAPPEDWINDOW
+	if opts.Undecorated {
+		// without a border the user cannot resize the window either
+		exStyle, style = 0, w32.WS_POPUP|w32.WS_SYSMENU|w32.WS_MINIMIZEBOX|w32.WS_MAXIMIZEBOX
+	}
+	if opts.FixedSize {
+		style &^= w32.WS_MAXIMIZEBOX | w32.WS_SIZEBOX
+	}
+	if opts.AlwaysOnTop {
+		exStyle |= w32.WS_EX_TOPMOST
+	}
+	// frame gives the size of the window that has a client area of size
+	frame := func(size image.Point) image.Point {
+		cr := &w32.RECT{Right: int32(size.X), Bottom: int32(size.Y)}
+		w32.AdjustWindowRectEx(cr, style, false, exStyle)
+		return image.Pt(int(cr.Right-cr.Left), int(cr.Bottom-cr.Top))
+	}
+
+	size := frame(image.Pt(opts.Width, opts.Height))
+	x, y := w32.CW_USEDEFAULT, w32.CW_USEDEFAULT
+	if opts.Position != nil {
+		x, y = opts.Position.X, opts.Position.Y
+	}
+	var parent *Window
+	if p, ok := opts.Parent.(*Window); ok {
+		parent = p
 	}
-	w32.AdjustWindowRectEx(cr, w32.WS_OVERLAPPEDWINDOW, false, w32.WS_EX_CLIENTEDGE)
-	width = int(cr.Right - cr.Left)
-	height = int(cr.Bottom - cr.Top)
-	hwnd, err := CreateWindow(WIN_CLASSNAME, nil, w32.WS_EX_CLIENTEDGE, w32.WS_OVERLAPPEDWINDOW, width, height)
+	hwnd, err := CreateWindow(WIN_CLASSNAME, parent, exStyle, style, x, y, size.X, size.Y)
 	if err != nil {
 		return
 	}
 
 	w = &Window{
 		hwnd:       hwnd,
-		buffer:     NewDIB(image.Rect(0, 0, width, height)),
-		bufferback: NewDIB(image.Rect(0, 0, width, height)),
-		events:     make(chan interface{}, 16),
+		buffer:     NewDIB(image.Rect(0, 0, opts.Width, opts.Height)),
+		bufferback: NewDIB(image.Rect(0, 0, opts.Width, opts.Height)),
+		events:     make(chan wde.Event, 16),
+		showCmd:    w32.SW_SHOWDEFAULT,
+	}
+	if opts.MinSize != image.ZP {
+		w.minTrack = frame(opts.MinSize)
+	}
+	if opts.MaxSize != image.ZP {
+		w.maxTrack = frame(opts.MaxSize)
+		// a zero dimension has no limit
+		if opts.MaxSize.X == 0 {
+			w.maxTrack.X = 0
+		}
+		if opts.MaxSize.Y == 0 {
+			w.maxTrack.Y = 0
+		}
 	}
 	w.InitEventData()
 
 	RegMsgHandler(w)
 
-	w.Center()
+	if opts.Title != "" {
+		w.SetTitle(opts.Title)
+	}
+	if opts.Position == nil {
+		w.Center()
+	}
+	switch opts.State {
+	case wde.StateMaximized:
+		w.showCmd = w32.SW_SHOWMAXIMIZED
+	case wde.StateMinimized:
+		w.showCmd = w32.SW_SHOWMINIMIZED
+	case wde.StateFullscreen:
+		// this is the window's thread, so there is no need to post
+		w.setFullscreen(true)
+	}
 
 	return
 }
 
 func NewWindow(width, height int) (w *Window, err error) {
+	return NewWindowWithOptions(wde.WindowOptions{Width: width, Height: height})
+}
+
+/*
+NewWindowWithOptions creates a window as opts describe. Parent, which must
+be a window of this backend, becomes the window's owner.
+*/
+func NewWindowWithOptions(opts wde.WindowOptions) (w *Window, err error) {
 	ready := make(chan error, 1)
 
 	go func(ready chan error) {
 		runtime.LockOSThread()
 		var err error
-		w, err = makeTheWindow(width, height)
+		w, err = makeTheWindow(opts)
 		ready <- err
 		w.HandleWndMessages()
 	}(ready)
@@ -121,7 +193,7 @@ func (this *Window) SetTitle(title string) {
 }
 
 func (this *Window) SetSize(width, height int) {
-	x, y := this.Pos()
+	x, y := this.Position()
 	w32.MoveWindow(this.hwnd, x, y, width, height, true)
 }
 
@@ -141,7 +213,92 @@ func (w *Window) LockSize(lock bool) {
 }
 
 func (this *Window) Show() {
-	w32.ShowWindow(this.hwnd, w32.SW_SHOWDEFAULT)
+	w32.ShowWindow(this.hwnd, this.showCmd)
+	this.showCmd = w32.SW_SHOWDEFAULT
+}
+
+/*
+SetFullscreen makes the window a borderless one covering the monitor that
+holds its middle. Windows has no fullscreen state of its own, so the frame
+and where the window was are saved for when it is put back. The window's
+thread does the work, as it reads them while the window resizes, and
+SetFullscreen returns without waiting for it.
+*/
+func (this *Window) SetFullscreen(fullscreen bool) {
+	var wparam uintptr
+	if fullscreen {
+		wparam = 1
+	}
+	w32.PostMessage(this.hwnd, WM_WDE_SETFULLSCREEN, wparam, 0)
+}
+
+// setFullscreen does the work of SetFullscreen on the window's thread. It
+// reports whether the window went in or out of fullscreen.
+func (this *Window) setFullscreen(fullscreen bool) bool {
+	if fullscreen == this.fullscreen {
+		return false
+	}
+	if !fullscreen {
+		this.fullscreen = false
+		w32.SetWindowLongPtr(this.hwnd, w32.GWL_STYLE, this.restoreStyle)
+		r := this.restoreRect
+		w32.SetWindowPos(this.hwnd, 0,
+			int(r.Left), int(r.Top), int(r.Right-r.Left), int(r.Bottom-r.Top),
+			w32.SWP_FRAMECHANGED|w32.SWP_NOZORDER|w32.SWP_NOOWNERZORDER)
+		return true
+	}
+	if IsZoomed(this.hwnd) || IsIconic(this.hwnd) {
+		w32.ShowWindow(this.hwnd, w32.SW_RESTORE)
+	}
+	screens, _ := Screens()
+	r := rectangle(*w32.GetWindowRect(this.hwnd))
+	s, ok := wde.ScreenAt(screens, r.Min.Add(r.Size().Div(2)))
+	if !ok {
+		return false
+	}
+	this.restoreStyle = w32.GetWindowLongPtr(this.hwnd, w32.GWL_STYLE)
+	this.restoreRect = *w32.GetWindowRect(this.hwnd)
+	this.fullscreen = true
+	w32.SetWindowLongPtr(this.hwnd, w32.GWL_STYLE, this.restoreStyle&^w32.WS_OVERLAPPEDWINDOW)
+	w32.SetWindowPos(this.hwnd, w32.HWND_TOP,
+		s.Bounds.Min.X, s.Bounds.Min.Y, s.Bounds.Dx(), s.Bounds.Dy(),
+		w32.SWP_FRAMECHANGED|w32.SWP_NOOWNERZORDER)
+	return true
+}
+
+// Maximize, Minimize and Restore, like SetFullscreen, leave the work to the
+// window's thread and return without waiting for it.
+func (this *Window) Maximize() {
+	w32.PostMessage(this.hwnd, WM_WDE_SETSTATE, uintptr(wde.StateMaximized), 0)
+}
+
+// Minimize leaves a fullscreen window fullscreen, to come back as it was.
+func (this *Window) Minimize() {
+	w32.PostMessage(this.hwnd, WM_WDE_SETSTATE, uintptr(wde.StateMinimized), 0)
+}
+
+func (this *Window) Restore() {
+	w32.PostMessage(this.hwnd, WM_WDE_SETSTATE, uintptr(wde.StateNormal), 0)
+}
+
+// setState does the work of Maximize, Minimize and Restore on the window's
+// thread.
+func (this *Window) setState(state wde.WindowState) {
+	switch state {
+	case wde.StateMaximized:
+		this.setFullscreen(false)
+		w32.ShowWindow(this.hwnd, w32.SW_MAXIMIZE)
+	case wde.StateMinimized:
+		w32.ShowWindow(this.hwnd, w32.SW_MINIMIZE)
+	case wde.StateNormal:
+		if IsIconic(this.hwnd) {
+			w32.ShowWindow(this.hwnd, w32.SW_RESTORE)
+		}
+		// a fullscreen window is restored by leaving fullscreen
+		if !this.setFullscreen(false) && IsZoomed(this.hwnd) {
+			w32.ShowWindow(this.hwnd, w32.SW_RESTORE)
+		}
+	}
 }
 
 func (this *Window) Screen() wde.Image {
@@ -149,15 +306,29 @@ func (this *Window) Screen() wde.Image {
 }
 
 func (this *Window) FlushImage(bounds ...image.Rectangle) {
-	this.bufferback = NewDIB(this.buffer.Bounds())
-	*this.bufferback = *this.buffer
+	rects := wde.FlushRects(this.buffer.Bounds(), bounds)
+
+	// keep a copy of the frame for WM_PAINT to repaint from
+	if this.bufferback.Bounds() != this.buffer.Bounds() {
+		this.bufferback = NewDIB(this.buffer.Bounds())
+		copy(this.bufferback.Pix, this.buffer.Pix)
+	} else {
+		for _, r := range rects {
+			for y := r.Min.Y; y < r.Max.Y; y++ {
+				i, j := this.buffer.PixOffset(r.Min.X, y), this.buffer.PixOffset(r.Max.X, y)
+				copy(this.bufferback.Pix[i:j], this.buffer.Pix[i:j])
+			}
+		}
+	}
 
 	hdc := w32.GetDC(this.hwnd)
-	this.blitImage(hdc, this.buffer)
+	for _, r := range rects {
+		this.blitImage(hdc, this.buffer, r)
+	}
 	w32.DeleteDC(hdc)
 }
 
-func (this *Window) EventChan() <-chan interface{} {
+func (this *Window) EventChan() <-chan wde.Event {
 	return this.events
 }
 
@@ -174,10 +345,18 @@ func (this *Window) Close() error {
 // Non - interface methods
 /////////////////////////////
 
-func (this *Window) blitImage(hdc w32.HDC, buffer *DIB) {
-	bounds := buffer.Bounds()
-	width := bounds.Dx()
-	height := bounds.Dy()
+// blitImage paints the part r of buffer onto the same place in hdc.
+func (this *Window) blitImage(hdc w32.HDC, buffer *DIB, r image.Rectangle) {
+	if r != buffer.Bounds() {
+		// SetDIBitsToDevice wants the rows it sends packed end to end
+		sub := NewDIB(r)
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			copy(sub.Pix[sub.PixOffset(r.Min.X, y):sub.PixOffset(r.Max.X, y)], buffer.Pix[buffer.PixOffset(r.Min.X, y):])
+		}
+		buffer = sub
+	}
+	width := r.Dx()
+	height := r.Dy()
 
 	var bi w32.BITMAPINFO
 	bi.BmiHeader.BiSize = uint32(unsafe.Sizeof(bi.BmiHeader))
@@ -188,7 +367,7 @@ func (this *Window) blitImage(hdc w32.HDC, buffer *DIB) {
 	bi.BmiHeader.BiCompression = w32.BI_RGB
 
 	w32.SetDIBitsToDevice(hdc,
-		0, 0,
+		r.Min.X, r.Min.Y,
 		width, height,
 		0, 0,
 		0, uint(height),
@@ -206,34 +385,49 @@ func (this *Window) HandleWndMessages() {
 	}
 }
 
-func (this *Window) Pos() (x, y int) {
+func (this *Window) Position() (x, y int) {
 	rect := w32.GetWindowRect(this.hwnd)
 	return int(rect.Left), int(rect.Top)
 }
 
+func (this *Window) SetPosition(x, y int) {
+	w32.SetWindowPos(this.hwnd, 0, x, y, 0, 0, w32.SWP_NOSIZE|w32.SWP_NOZORDER|w32.SWP_NOACTIVATE)
+}
+
+// Pos is the old name of Position.
+//
+// Deprecated: use Position.
+func (this *Window) Pos() (x, y int) {
+	return this.Position()
+}
+
+// SetPos is the old name of SetPosition. It no longer resizes the window.
+//
+// Deprecated: use SetPosition.
 func (this *Window) SetPos(x, y int) {
-	w, h := this.Size()
-	if w == 0 {
-		w = 100
-	}
-	if h == 0 {
-		h = 25
-	}
-	w32.MoveWindow(this.hwnd, x, y, w, h, true)
+	this.SetPosition(x, y)
 }
 
+// Center moves the window to the middle of the work area of the monitor
+// that holds its middle.
 func (this *Window) Center() {
-	sWidth := w32.GetSystemMetrics(w32.SM_CXFULLSCREEN)
-	sHeight := w32.GetSystemMetrics(w32.SM_CYFULLSCREEN)
-
-	if sWidth != 0 && sHeight != 0 {
-		w, h := this.Size()
-		this.SetPos((sWidth/2)-(w/2), (sHeight/2)-(h/2))
+	screens, _ := Screens()
+	// the frame counts, as it does for SetPosition
+	r := rectangle(*w32.GetWindowRect(this.hwnd))
+	s, ok := wde.ScreenAt(screens, r.Min.Add(r.Size().Div(2)))
+	if !ok {
+		return
 	}
+	p := s.WorkArea.Min.Add(s.WorkArea.Size().Sub(r.Size()).Div(2))
+	this.SetPosition(p.X, p.Y)
+}
+
+func (this *Window) Scale() float64 {
+	return scaleForDPI(windowDPI(this.hwnd))
 }
 
 func (this *Window) Repaint() {
 	hdc := w32.GetDC(this.hwnd)
-	this.blitImage(hdc, this.bufferback)
+	this.blitImage(hdc, this.bufferback, this.bufferback.Bounds())
 	w32.DeleteDC(hdc)
 }
@@ -0,0 +1,127 @@
+/*
+   Copyright 2012 the go.wde authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package xgb
+
+import (
+	"github.com/BurntSushi/xgb/xproto"
+	"unicode"
+)
+
+// isModifierKeysym reports whether ks belongs to a modifier key, which
+// leaves a pending dead key alone.
+func isModifierKeysym(ks xproto.Keysym) bool {
+	return (ks >= 0xffe1 && ks <= 0xffee) || (ks >= 0xfe01 && ks <= 0xfe13) || ks == 0xff7e
+}
+
+type deadKey struct {
+	// spacing is what the dead key types on its own
+	spacing  rune
+	combined map[rune]rune
+}
+
+func newDeadKey(spacing rune, pairs string) (dk deadKey) {
+	dk.spacing = spacing
+	dk.combined = map[rune]rune{}
+	rs := []rune(pairs)
+	for i := 0; i+1 < len(rs); i += 2 {
+		dk.combined[rs[i]] = rs[i+1]
+	}
+	return
+}
+
+// deadKeys maps the dead_* keysyms to the accents they add, as pairs of
+// base letter and accented letter.
+var deadKeys = map[xproto.Keysym]deadKey{
+	0xfe50: newDeadKey('`', "aàeèiìoòuùAÀEÈIÌOÒUÙnǹNǸ"),
+	0xfe51: newDeadKey('´', "aáeéiíoóuúyýAÁEÉIÍOÓUÚYÝcćCĆnńNŃsśSŚzźZŹlĺLĹrŕRŔgǵGǴ"),
+	0xfe52: newDeadKey('^', "aâeêiîoôuûAÂEÊIÎOÔUÛcĉCĈgĝGĜhĥHĤjĵJĴsŝSŜwŵWŴyŷYŶ"),
+	0xfe53: newDeadKey('~', "aãoõnñiĩuũAÃOÕNÑIĨUŨ"),
+	0xfe54: newDeadKey('¯', "aāeēiīoōuūAĀEĒIĪOŌUŪ"),
+	0xfe55: newDeadKey('˘', "aăgğuŭAĂGĞUŬ"),
+	0xfe56: newDeadKey('˙', "cċeėgġzżCĊEĖGĠIİZŻ"),
+	0xfe57: newDeadKey('¨', "aäeëiïoöuüyÿAÄEËIÏOÖUÜYŸ"),
+	0xfe58: newDeadKey('˚', "aåuůAÅUŮ"),
+	0xfe59: newDeadKey('˝', "oőuűOŐUŰ"),
+	0xfe5a: newDeadKey('ˇ', "cčdďeělľnňrřsštťzžCČDĎEĚLĽNŇRŘSŠTŤZŽ"),
+	0xfe5b: newDeadKey('¸', "cçgģkķlļnņrŗsştţCÇGĢKĶLĻNŅRŖSŞTŢ"),
+	0xfe5c: newDeadKey('˛', "aąeęiįuųAĄEĘIĮUŲ"),
+}
+
+/*
+composer turns keysyms into text, combining dead keys with the letter
+that follows them, from the table above rather than the locale's Compose
+file. While a dead key is pending, its spacing accent is the composition.
+*/
+type composer struct {
+	pending *deadKey
+}
+
+/*
+feed gives the composer the keysym of a pressed key, with caps lock
+applied if lock is set. It returns any text that is now committed, and
+whether the text being composed changed; if it did, preedit is the new
+composition.
+*/
+func (c *composer) feed(ks xproto.Keysym, lock bool) (commit, preedit string, changed bool) {
+	if isModifierKeysym(ks) {
+		return
+	}
+	if dk, ok := deadKeys[ks]; ok {
+		if c.pending != nil {
+			commit = string(c.pending.spacing)
+			if c.pending.spacing == dk.spacing {
+				// pressing a dead key twice types the accent
+				c.pending = nil
+				return commit, "", true
+			}
+		}
+		c.pending = &dk
+		return commit, string(dk.spacing), true
+	}
+
+	r := keysymRune(ks)
+	if lock {
+		r = unicode.ToUpper(r)
+	}
+	if c.pending == nil {
+		if r != 0 {
+			commit = string(r)
+		}
+		return
+	}
+
+	dk := c.pending
+	c.pending = nil
+	switch {
+	case r == 0:
+		// any other key abandons the composition
+	case r == ' ':
+		commit = string(dk.spacing)
+	case dk.combined[r] != 0:
+		commit = string(dk.combined[r])
+	default:
+		commit = string(dk.spacing) + string(r)
+	}
+	return commit, "", true
+}
+
+// reset abandons any composition, reporting whether there was one.
+func (c *composer) reset() (changed bool) {
+	changed = c.pending != nil
+	c.pending = nil
+	return
+}
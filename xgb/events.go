@@ -18,15 +18,41 @@ package xgb
 
 import (
 	"fmt"
+	"github.com/BurntSushi/xgb"
+	"github.com/BurntSushi/xgb/randr"
 	"github.com/BurntSushi/xgb/xproto"
 	"github.com/BurntSushi/xgbutil/icccm"
-	"github.com/BurntSushi/xgbutil/keybind"
 	"github.com/BurntSushi/xgbutil/xevent"
 	"github.com/BurntSushi/xgbutil/xgraphics"
+	"github.com/BurntSushi/xgbutil/xprop"
 	"github.com/skelterjohn/go.wde"
 	"image"
+	"time"
+	"unicode/utf8"
 )
 
+/*
+A syntheticConfigureNotify is a ConfigureNotify event that a client sent,
+as window managers do to report moves in root coordinates, rather than one
+from the server, whose coordinates are relative to the parent. xgb drops the
+bit that tells them apart, so init wraps the event's constructor to keep
+it.
+*/
+type syntheticConfigureNotify struct {
+	xproto.ConfigureNotifyEvent
+}
+
+func init() {
+	newEvent := xgb.NewEventFuncs[xproto.ConfigureNotify]
+	xgb.NewEventFuncs[xproto.ConfigureNotify] = func(buf []byte) xgb.Event {
+		e := newEvent(buf)
+		if buf[0]&0x80 != 0 {
+			return syntheticConfigureNotify{e.(xproto.ConfigureNotifyEvent)}
+		}
+		return e
+	}
+}
+
 func buttonForDetail(detail xproto.Button) wde.Button {
 	switch detail {
 	case 1:
@@ -35,12 +61,70 @@ func buttonForDetail(detail xproto.Button) wde.Button {
 		return wde.MiddleButton
 	case 3:
 		return wde.RightButton
+	}
+	return 0
+}
+
+// scrollForDetail returns the scrolling that the core protocol reports as
+// a press of one of the buttons from 4 to 7, one notch at a time.
+func scrollForDetail(detail xproto.Button) (dx, dy float64, ok bool) {
+	switch detail {
 	case 4:
-		return wde.WheelUpButton
+		return 0, 1, true
 	case 5:
-		return wde.WheelDownButton
+		return 0, -1, true
+	case 6:
+		return -1, 0, true
+	case 7:
+		return 1, 0, true
+	}
+	return
+}
+
+// modifiersForState decodes the modifier bits of an X event's state, using
+// the usual assignment of Alt to Mod1, Num Lock to Mod2 and Super to Mod4.
+func modifiersForState(state uint16) (mods wde.Modifiers) {
+	if state&xproto.ModMaskShift != 0 {
+		mods |= wde.ModShift
+	}
+	if state&xproto.ModMaskControl != 0 {
+		mods |= wde.ModControl
+	}
+	if state&xproto.ModMask1 != 0 {
+		mods |= wde.ModAlt
+	}
+	if state&xproto.ModMask4 != 0 {
+		mods |= wde.ModSuper
+	}
+	if state&xproto.ModMaskLock != 0 {
+		mods |= wde.ModCapsLock
+	}
+	if state&xproto.ModMask2 != 0 {
+		mods |= wde.ModNumLock
+	}
+	return
+}
+
+// sendText sends a TextInputEvent for commit, if it is not empty, and then
+// a TextCompositionEvent for preedit if the composition changed.
+func (w *Window) sendText(when time.Duration, mods wde.Modifiers, commit, preedit string, changed bool) {
+	if commit != "" {
+		var te wde.TextInputEvent
+		te.Source = w
+		te.When = when
+		te.Mods = mods
+		te.Text = commit
+		w.events <- te
+	}
+	if changed {
+		var ce wde.TextCompositionEvent
+		ce.Source = w
+		ce.When = when
+		ce.Mods = mods
+		ce.Text = preedit
+		ce.Cursor = utf8.RuneCountInString(preedit)
+		w.events <- ce
 	}
-	return 0
 }
 
 func (w *Window) handleEvents() {
@@ -50,39 +134,87 @@ func (w *Window) handleEvents() {
 	var button wde.Button
 
 	downKeys := map[string]bool{}
+	focused := false
+	var damage []image.Rectangle
+	var clock wde.MillisecondClock
+	var clicks wde.ClickCounter
+	// pos is where the window was last reported to be
+	pos := w.origin
+	// parent is the window the window manager put the window in, and inset
+	// is where the window is within the frame it drew there
+	parent := w.xu.RootWin()
+	inset := w.frameInset()
+	// screens are the monitors, kept up to date from RandR
+	screens := listScreens(w.xu)
+	state := wde.StateNormal
+	var comp composer
 
 	for {
 		e, err := w.conn.WaitForEvent()
 
+		if e == nil && err == nil {
+			// the connection has been closed
+			break
+		}
 		if err != nil {
 			fmt.Println("[go.wde X error] ", err)
 			continue
 		}
 
+		synthetic := false
+		if se, ok := e.(syntheticConfigureNotify); ok {
+			e, synthetic = se.ConfigureNotifyEvent, true
+		}
+
 		switch e := e.(type) {
 
 		case xproto.ButtonPressEvent:
+			if dx, dy, ok := scrollForDetail(e.Detail); ok {
+				var se wde.ScrollEvent
+				se.Source = w
+				se.When = clock.Stamp(uint32(e.Time))
+				se.Mods = modifiersForState(e.State)
+				se.Where.X = int(e.EventX)
+				se.Where.Y = int(e.EventY)
+				se.DeltaX, se.DeltaY = dx, dy
+				w.events <- se
+				break
+			}
 			button = button | buttonForDetail(e.Detail)
 			var bpe wde.MouseDownEvent
+			bpe.Source = w
+			bpe.When = clock.Stamp(uint32(e.Time))
+			bpe.Mods = modifiersForState(e.State)
 			bpe.Which = buttonForDetail(e.Detail)
 			bpe.Where.X = int(e.EventX)
 			bpe.Where.Y = int(e.EventY)
+			bpe.Clicks = clicks.Press(bpe.Which, bpe.Where, bpe.When)
 			lastX = int32(e.EventX)
 			lastY = int32(e.EventY)
 			w.events <- bpe
 
 		case xproto.ButtonReleaseEvent:
+			if _, _, ok := scrollForDetail(e.Detail); ok {
+				break
+			}
 			button = button & ^buttonForDetail(e.Detail)
 			var bue wde.MouseUpEvent
+			bue.Source = w
+			bue.When = clock.Stamp(uint32(e.Time))
+			bue.Mods = modifiersForState(e.State)
 			bue.Which = buttonForDetail(e.Detail)
 			bue.Where.X = int(e.EventX)
 			bue.Where.Y = int(e.EventY)
+			bue.Clicks = clicks.Release(bue.Which)
 			lastX = int32(e.EventX)
 			lastY = int32(e.EventY)
 			w.events <- bue
 
 		case xproto.LeaveNotifyEvent:
 			var wee wde.MouseExitedEvent
+			wee.Source = w
+			wee.When = clock.Stamp(uint32(e.Time))
+			wee.Mods = modifiersForState(e.State)
 			wee.Where.X = int(e.EventX)
 			wee.Where.Y = int(e.EventY)
 			if lastX != noX {
@@ -97,6 +229,9 @@ func (w *Window) handleEvents() {
 			w.events <- wee
 		case xproto.EnterNotifyEvent:
 			var wee wde.MouseEnteredEvent
+			wee.Source = w
+			wee.When = clock.Stamp(uint32(e.Time))
+			wee.Mods = modifiersForState(e.State)
 			wee.Where.X = int(e.EventX)
 			wee.Where.Y = int(e.EventY)
 			if lastX != noX {
@@ -112,6 +247,9 @@ func (w *Window) handleEvents() {
 
 		case xproto.MotionNotifyEvent:
 			var mme wde.MouseMovedEvent
+			mme.Source = w
+			mme.When = clock.Stamp(uint32(e.Time))
+			mme.Mods = modifiersForState(e.State)
 			mme.Where.X = int(e.EventX)
 			mme.Where.Y = int(e.EventY)
 			if lastX != noX {
@@ -134,28 +272,94 @@ func (w *Window) handleEvents() {
 
 		case xproto.KeyPressEvent:
 			var ke wde.KeyEvent
-			code := keybind.LookupString(w.xu, e.State, e.Detail)
-			ke.Key = keyForCode(code)
-			w.events <- wde.KeyDownEvent(ke)
+			ke.Source = w
+			ke.When = clock.Stamp(uint32(e.Time))
+			ke.Mods = modifiersForState(e.State)
+			ke.Key = keyForCode(w.xu, e.Detail)
+			ke.Scancode = physicalKeys[e.Detail].scancode
+			ks := keysymForState(w.xu, e.State, e.Detail)
+			lock := e.State&xproto.ModMaskLock != 0
+			// with detectable auto-repeat, a held key sends no releases
+			repeat := downKeys[ke.Key]
+			w.events <- wde.KeyDownEvent{KeyEvent: ke, Repeat: repeat}
 			downKeys[ke.Key] = true
 			kpe := wde.KeyTypedEvent{
 				KeyEvent: ke,
-				Glyph:    letterForCode(code),
+				Repeat:   repeat,
+				Glyph:    glyphForKeysym(ks, lock),
 				Chord:    wde.ConstructChord(downKeys),
 			}
 			w.events <- kpe
 
+			if ke.Mods&(wde.ModControl|wde.ModAlt|wde.ModSuper) == 0 {
+				commit, preedit, changed := comp.feed(ks, lock)
+				w.sendText(ke.When, ke.Mods, commit, preedit, changed)
+			}
+
 		case xproto.KeyReleaseEvent:
 			var ke wde.KeyUpEvent
-			ke.Key = keyForCode(keybind.LookupString(w.xu, e.State, e.Detail))
+			ke.Source = w
+			ke.When = clock.Stamp(uint32(e.Time))
+			ke.Mods = modifiersForState(e.State)
+			ke.Key = keyForCode(w.xu, e.Detail)
+			ke.Scancode = physicalKeys[e.Detail].scancode
 			delete(downKeys, ke.Key)
 			w.events <- ke
 
+		case xproto.MappingNotifyEvent:
+			// the layout changed, or a modifier was remapped
+			if e.Request != xproto.MappingPointer {
+				w.loadKeymap()
+			}
+
+		case xproto.FocusInEvent:
+			if e.Detail == xproto.NotifyDetailPointer || focused {
+				break
+			}
+			focused = true
+			var fe wde.FocusEvent
+			fe.Source = w
+			fe.When = clock.Now()
+			fe.Gained = true
+			w.events <- fe
+
+		case xproto.FocusOutEvent:
+			if e.Detail == xproto.NotifyDetailPointer || !focused {
+				break
+			}
+			focused = false
+			// key releases go to whichever window has the focus now, so
+			// forget what was held rather than leave it stuck in chords
+			downKeys = map[string]bool{}
+			var fe wde.FocusEvent
+			fe.Source = w
+			fe.When = clock.Now()
+			w.sendText(fe.When, 0, "", "", comp.reset())
+			w.events <- fe
+
+		case xproto.ExposeEvent:
+			damage = append(damage, image.Rect(int(e.X), int(e.Y), int(e.X)+int(e.Width), int(e.Y)+int(e.Height)))
+			if e.Count > 0 {
+				// more of the same exposure is on its way
+				break
+			}
+			// the server has already filled the damage in from the
+			// window's background, the pixmap of the last flushed frame
+			var ee wde.ExposeEvent
+			ee.Source = w
+			ee.When = clock.Now()
+			ee.Rects = damage
+			damage = nil
+			w.events <- ee
+
 		case xproto.ConfigureNotifyEvent:
 			var re wde.ResizeEvent
+			re.Source = w
+			re.When = clock.Now()
 			re.Width = int(e.Width)
 			re.Height = int(e.Height)
-			if re.Width != w.width || re.Height != w.height {
+			resized := re.Width != w.width || re.Height != w.height
+			if resized {
 				w.width, w.height = re.Width, re.Height
 
 				w.bufferLck.Lock()
@@ -166,15 +370,79 @@ func (w *Window) handleEvents() {
 				w.events <- re
 			}
 
+			// the window manager tells us of moves with synthetic
+			// ConfigureNotify events, in root coordinates; real ones are
+			// relative to the parent, which is the frame once there is
+			// one, so only those need translating
+			at, ok := image.Pt(int(e.X), int(e.Y)), true
+			if !synthetic && parent != w.xu.RootWin() {
+				at, ok = w.rootPosition()
+			}
+			moved := false
+			if at = at.Sub(inset); ok && at != pos {
+				moved = true
+				pos = at
+				var me wde.MoveEvent
+				me.Source = w
+				me.When = clock.Now()
+				me.X, me.Y = pos.X, pos.Y
+				w.events <- me
+			}
+
+			if !moved && !resized {
+				break
+			}
+			r := image.Rectangle{pos, pos.Add(image.Pt(w.width, w.height))}
+			if scale := scaleOf(screens, r); scale != w.Scale() {
+				w.scaleLck.Lock()
+				w.scale = scale
+				w.scaleLck.Unlock()
+				var se wde.ScaleChangedEvent
+				se.Source = w
+				se.When = clock.Now()
+				se.Scale = scale
+				w.events <- se
+			}
+
 		case xproto.ClientMessageEvent:
-			if icccm.IsDeleteProtocol(w.xu, xevent.ClientMessageEvent{&e}) {
-				w.events <- wde.CloseEvent{}
+			if icccm.IsDeleteProtocol(w.xu, xevent.ClientMessageEvent{ClientMessageEvent: &e}) {
+				var ce wde.CloseEvent
+				ce.Source = w
+				// WM_PROTOCOLS messages carry their timestamp in the second field
+				ce.When = clock.Stamp(e.Data.Data32[1])
+				w.events <- ce
 			}
 		case xproto.DestroyNotifyEvent:
 		case xproto.ReparentNotifyEvent:
+			parent = e.Parent
 		case xproto.MapNotifyEvent:
 		case xproto.UnmapNotifyEvent:
 		case xproto.PropertyNotifyEvent:
+			name, err := xprop.AtomName(w.xu, e.Atom)
+			if err != nil {
+				break
+			}
+			if name == "_NET_FRAME_EXTENTS" {
+				inset = w.frameInset()
+				break
+			}
+			if name != "_NET_WM_STATE" && name != "WM_STATE" {
+				break
+			}
+			if ns := w.state(); ns != state {
+				state = ns
+				var se wde.WindowStateEvent
+				se.Source = w
+				se.When = clock.Stamp(uint32(e.Time))
+				se.State = state
+				w.events <- se
+			}
+
+		case randr.ScreenChangeNotifyEvent:
+			screens = listScreens(w.xu)
+
+		case xproto.NoExposureEvent:
+			// a copy with graphics exposures on found nothing obscured
 
 		default:
 			fmt.Printf("unhandled event: type %T\n%+v\n", e, e)
@@ -185,7 +453,7 @@ func (w *Window) handleEvents() {
 	close(w.events)
 }
 
-func (w *Window) EventChan() (events <-chan interface{}) {
+func (w *Window) EventChan() (events <-chan wde.Event) {
 	events = w.events
 
 	return
@@ -0,0 +1,39 @@
+/*
+   Copyright 2012 the go.wde authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package xgb
+
+import (
+	"github.com/BurntSushi/xgb"
+	"github.com/BurntSushi/xgb/xproto"
+	"testing"
+)
+
+func TestSyntheticConfigureNotify(t *testing.T) {
+	buf := xproto.ConfigureNotifyEvent{X: 10, Y: 20, Width: 30, Height: 40}.Bytes()
+	newEvent := xgb.NewEventFuncs[xproto.ConfigureNotify]
+	if _, ok := newEvent(buf).(xproto.ConfigureNotifyEvent); !ok {
+		t.Errorf("server's ConfigureNotify decoded as %T", newEvent(buf))
+	}
+	buf[0] |= 0x80
+	e, ok := newEvent(buf).(syntheticConfigureNotify)
+	if !ok {
+		t.Fatalf("sent ConfigureNotify decoded as %T", newEvent(buf))
+	}
+	if e.X != 10 || e.Y != 20 || e.Width != 30 || e.Height != 40 {
+		t.Errorf("sent ConfigureNotify decoded as %+v", e.ConfigureNotifyEvent)
+	}
+}
@@ -1,132 +1,357 @@
 package xgb
 
 import (
+	"github.com/BurntSushi/xgb/xproto"
+	"github.com/BurntSushi/xgbutil"
+	"github.com/BurntSushi/xgbutil/keybind"
 	"github.com/skelterjohn/go.wde"
+	"unicode"
 )
 
-func keyForCode(code string) (key string) {
-	key = codeKeys[code]
-	if key == "" {
-		key = code
+/*
+keyForCode returns the wde key for keycode. It follows the layout, by
+looking at the keysym the key produces with no modifiers in the first
+group, so the key labelled "z" is wde.KeyZ on QWERTY and QWERTZ keyboards
+alike. Keys whose keysym means nothing to wde fall back to the key in the
+same place on a US keyboard.
+*/
+func keyForCode(xu *xgbutil.XUtil, keycode xproto.Keycode) (key string) {
+	ks := keybind.KeysymGet(xu, keycode, 0)
+	if key = keysymKeys[ks]; key != "" {
+		return
 	}
-	return
+	// keys of other scripts are named after the US key in their place,
+	// as shortcuts are bound to Latin letters
+	if r := keysymRune(ks); r != 0 && r < 0x250 {
+		return string(unicode.ToLower(r))
+	}
+	if key = physicalKeys[keycode].key; key != "" {
+		return
+	}
+	return keybind.KeysymToStr(ks)
 }
 
-func letterForCode(code string) (letter string) {
-	if len(code) == 1 {
-		letter = code
-	} else {
-		letter = longLetters[code]
+// glyphForKeysym returns what typing ks enters, with caps lock applied if
+// lock is set.
+func glyphForKeysym(ks xproto.Keysym, lock bool) (glyph string) {
+	r := keysymRune(ks)
+	if r == 0 {
+		return
+	}
+	if lock {
+		r = unicode.ToUpper(r)
 	}
-	return
+	return string(r)
 }
 
-var codeKeys map[string]string
-var longLetters = map[string]string{
-	"quoteleft":  "`",
-	"quoteright": "'",
-}
+/*
+keysymForState picks the keysym that keycode produces with the modifiers
+in state, following the core protocol's rules: the group in state picks a
+pair of columns, Shift the second of them, Mod5 (usually AltGr) the third
+and fourth columns, and Num Lock the second column of keypad keys.
+*/
+func keysymForState(xu *xgbutil.XUtil, state uint16, keycode xproto.Keycode) (ks xproto.Keysym) {
+	per := byte(keybind.KeyMapGet(xu).KeysymsPerKeycode)
+	get := func(col byte) xproto.Keysym {
+		if col >= per {
+			return 0
+		}
+		return keybind.KeysymGet(xu, keycode, col)
+	}
 
-func init() {
-	codeKeys = map[string]string{
-		"Shift_L":          wde.KeyLeftShift,
-		"Shift_R":          wde.KeyRightShift,
-		"Control_L":        wde.KeyLeftControl,
-		"Control_R":        wde.KeyRightControl,
-		"Hangul_switch":    wde.KeyLeftAlt,
-		"Alt_L":            wde.KeyLeftAlt,
-		"Alt_R":            wde.KeyRightAlt,
-		"Meta_L":           wde.KeyLeftSuper,
-		"Meta_R":           wde.KeyRightSuper,
-		"Super_L":          wde.KeyLeftSuper,
-		"Super_R":          wde.KeyRightSuper,
-		"Tab":              wde.KeyTab,
-		"ISO_Left_Tab":     wde.KeyTab,
-		"Return":           wde.KeyReturn,
-		"Up":               wde.KeyUpArrow,
-		"Down":             wde.KeyDownArrow,
-		"Left":             wde.KeyLeftArrow,
-		"Right":            wde.KeyRightArrow,
-		" ":                wde.KeySpace,
-		"Escape":           wde.KeyEscape,
-		"!":                wde.Key1,
-		"@":                wde.Key2,
-		"#":                wde.Key3,
-		"$":                wde.Key4,
-		"%":                wde.Key5,
-		"^":                wde.Key6,
-		"&":                wde.Key7,
-		"*":                wde.Key8,
-		"(":                wde.Key9,
-		")":                wde.Key0,
-		"_":                wde.KeyMinus,
-		"+":                wde.KeyEqual,
-		"|":                wde.KeyBackslash,
-		"BackSpace":        wde.KeyBackspace,
-		"Delete":           wde.KeyDelete,
-		"quoteleft":        wde.KeyBackTick,
-		"`":                wde.KeyBackTick,
-		"~":                wde.KeyBackTick,
-		"quoteright":       wde.KeyQuote,
-		"\"":               wde.KeyQuote,
-		"{":                wde.KeyLeftBracket,
-		"}":                wde.KeyRightBracket,
-		":":                wde.KeySemicolon,
-		"<":                wde.KeyComma,
-		">":                wde.KeyPeriod,
-		"?":                wde.KeySlash,
-		"F1":               wde.KeyF1,
-		"F2":               wde.KeyF2,
-		"F3":               wde.KeyF3,
-		"F4":               wde.KeyF4,
-		"F5":               wde.KeyF5,
-		"F6":               wde.KeyF6,
-		"F7":               wde.KeyF7,
-		"F8":               wde.KeyF8,
-		"F9":               wde.KeyF9,
-		"F10":              wde.KeyF10,
-		"F11":              wde.KeyF11,
-		"F12":              wde.KeyF12,
-		"F13":              wde.KeyF13,
-		"F14":              wde.KeyF14,
-		"F15":              wde.KeyF15,
-		"F16":              wde.KeyF16,
-		"L1":               wde.KeyF11,
-		"L2":               wde.KeyF12,
-		"XF86Tools":        wde.KeyF13,
-		"XF86Launch5":      wde.KeyF14,
-		"XF86Launch6":      wde.KeyF15,
-		"XF86Launch7":      wde.KeyF16,
-		"Num_Lock":         wde.KeyNumlock,
-		"KP_Equal":         wde.KeyPadEqual,
-		"Insert":           wde.KeyInsert,
-		"Home":             wde.KeyHome,
-		"Prior":            wde.KeyPrior,
-		"Next":             wde.KeyNext,
-		"Page_Up":          wde.KeyPrior,
-		"Page_Down":        wde.KeyNext,
-		"End":              wde.KeyEnd,
-		"KP_Insert":        wde.KeyPadInsert,
-		"KP_Delete":        wde.KeyPadDot,
-		"KP_Enter":         wde.KeyPadEnter,
-		"KP_End":           wde.KeyPadEnd,
-		"KP_Down":          wde.KeyPadDown,
-		"KP_Page_Down":     wde.KeyPadNext,
-		"KP_Next":          wde.KeyPadNext,
-		"KP_Left":          wde.KeyPadLeft,
-		"KP_Begin":         wde.KeyPadBegin,
-		"KP_Right":         wde.KeyPadRight,
-		"KP_Home":          wde.KeyPadHome,
-		"KP_Up":            wde.KeyPadUp,
-		"KP_Prior":         wde.KeyPadPrior,
-		"Caps_Lock":        wde.KeyCapsLock,
-		"Terminate_Server": wde.KeyBackspace,
+	shift := state&xproto.ModMaskShift != 0
+	if state&xproto.ModMask2 != 0 && isKeypadKeysym(get(1)) {
+		shift = !shift
+	}
+	level := byte(0)
+	if shift {
+		level = 1
+	}
+
+	group := byte(state>>13) & 3
+	if state&xproto.ModMask5 != 0 {
+		if ks = get(4 + 2*group + level); ks != 0 {
+			return
+		}
+	}
+	if ks = get(2*group + level); ks != 0 {
+		return
+	}
+	if ks = get(level); ks != 0 {
+		return
 	}
+	return get(0)
+}
+
+func isKeypadKeysym(ks xproto.Keysym) bool {
+	return ks >= 0xff80 && ks <= 0xffbd
+}
 
-	for l := byte('a'); l <= byte('z'); l++ {
-		codeKeys[string(l)] = string(l)
-		k := l + 'A' - 'a'
-		codeKeys[string(k)] = string(l)
+// keysymRune returns the character a keysym types, or 0 if it is not a
+// character keysym.
+func keysymRune(ks xproto.Keysym) rune {
+	switch {
+	case ks >= 0x20 && ks <= 0x7e, ks >= 0xa0 && ks <= 0xff:
+		return rune(ks)
+	case ks >= 0x01000100 && ks <= 0x0110ffff:
+		return rune(ks - 0x01000000)
+	case ks >= 0xffb0 && ks <= 0xffb9: // KP_0 to KP_9
+		return rune('0' + ks - 0xffb0)
+	case ks == 0x20ac: // EuroSign
+		return '€'
+	case ks < 0xff00:
+		return legacyRunes[ks]
 	}
+	return keypadRunes[ks]
+}
+
+var keypadRunes = map[xproto.Keysym]rune{
+	0xff80: ' ', // KP_Space
+	0xffaa: '*', // KP_Multiply
+	0xffab: '+', // KP_Add
+	0xffac: ',', // KP_Separator
+	0xffad: '-', // KP_Subtract
+	0xffae: '.', // KP_Decimal
+	0xffaf: '/', // KP_Divide
+	0xffbd: '=', // KP_Equal
+}
+
+// keysymKeys maps the keysyms that do not type a character, and a few that
+// do, to wde keys.
+var keysymKeys = map[xproto.Keysym]string{
+	0xffe1:     wde.KeyLeftShift,    // Shift_L
+	0xffe2:     wde.KeyRightShift,   // Shift_R
+	0xffe3:     wde.KeyLeftControl,  // Control_L
+	0xffe4:     wde.KeyRightControl, // Control_R
+	0xffe5:     wde.KeyCapsLock,     // Caps_Lock
+	0xffe7:     wde.KeyLeftSuper,    // Meta_L
+	0xffe8:     wde.KeyRightSuper,   // Meta_R
+	0xffe9:     wde.KeyLeftAlt,      // Alt_L
+	0xffea:     wde.KeyRightAlt,     // Alt_R
+	0xffeb:     wde.KeyLeftSuper,    // Super_L
+	0xffec:     wde.KeyRightSuper,   // Super_R
+	0xfe03:     wde.KeyRightAlt,     // ISO_Level3_Shift
+	0xff7e:     wde.KeyLeftAlt,      // Mode_switch, Hangul_switch
+	0xff09:     wde.KeyTab,          // Tab
+	0xfe20:     wde.KeyTab,          // ISO_Left_Tab
+	0xff0d:     wde.KeyReturn,       // Return
+	0xff1b:     wde.KeyEscape,       // Escape
+	0xff08:     wde.KeyBackspace,    // BackSpace
+	0xffff:     wde.KeyDelete,       // Delete
+	0xff63:     wde.KeyInsert,       // Insert
+	0xff50:     wde.KeyHome,         // Home
+	0xff51:     wde.KeyLeftArrow,    // Left
+	0xff52:     wde.KeyUpArrow,      // Up
+	0xff53:     wde.KeyRightArrow,   // Right
+	0xff54:     wde.KeyDownArrow,    // Down
+	0xff55:     wde.KeyPrior,        // Prior, Page_Up
+	0xff56:     wde.KeyNext,         // Next, Page_Down
+	0xff57:     wde.KeyEnd,          // End
+	0xff7f:     wde.KeyNumlock,      // Num_Lock
+	0x0020:     wde.KeySpace,        // space
+	0xffbe:     wde.KeyF1,           // F1
+	0xffbf:     wde.KeyF2,           // F2
+	0xffc0:     wde.KeyF3,           // F3
+	0xffc1:     wde.KeyF4,           // F4
+	0xffc2:     wde.KeyF5,           // F5
+	0xffc3:     wde.KeyF6,           // F6
+	0xffc4:     wde.KeyF7,           // F7
+	0xffc5:     wde.KeyF8,           // F8
+	0xffc6:     wde.KeyF9,           // F9
+	0xffc7:     wde.KeyF10,          // F10
+	0xffc8:     wde.KeyF11,          // F11, L1
+	0xffc9:     wde.KeyF12,          // F12, L2
+	0xffca:     wde.KeyF13,          // F13
+	0xffcb:     wde.KeyF14,          // F14
+	0xffcc:     wde.KeyF15,          // F15
+	0xffcd:     wde.KeyF16,          // F16
+	0x1008ff81: wde.KeyF13,          // XF86Tools
+	0x1008ff45: wde.KeyF14,          // XF86Launch5
+	0x1008ff46: wde.KeyF15,          // XF86Launch6
+	0x1008ff47: wde.KeyF16,          // XF86Launch7
+	0xff8d:     wde.KeyPadEnter,     // KP_Enter
+	0xff95:     wde.KeyPadHome,      // KP_Home
+	0xff96:     wde.KeyPadLeft,      // KP_Left
+	0xff97:     wde.KeyPadUp,        // KP_Up
+	0xff98:     wde.KeyPadRight,     // KP_Right
+	0xff99:     wde.KeyPadDown,      // KP_Down
+	0xff9a:     wde.KeyPadPrior,     // KP_Prior, KP_Page_Up
+	0xff9b:     wde.KeyPadNext,      // KP_Next, KP_Page_Down
+	0xff9c:     wde.KeyPadEnd,       // KP_End
+	0xff9d:     wde.KeyPadBegin,     // KP_Begin
+	0xff9e:     wde.KeyPadInsert,    // KP_Insert
+	0xff9f:     wde.KeyPadDelete,    // KP_Delete
+	0xffaa:     wde.KeyPadStar,      // KP_Multiply
+	0xffab:     wde.KeyPadPlus,      // KP_Add
+	0xffac:     wde.KeyPadComma,     // KP_Separator
+	0xffad:     wde.KeyPadMinus,     // KP_Subtract
+	0xffae:     wde.KeyPadDot,       // KP_Decimal
+	0xffaf:     wde.KeyPadSlash,     // KP_Divide
+	0xffbd:     wde.KeyPadEqual,     // KP_Equal
+	0xffb0:     wde.KeyPad0,         // KP_0
+	0xffb1:     wde.KeyPad1,         // KP_1
+	0xffb2:     wde.KeyPad2,         // KP_2
+	0xffb3:     wde.KeyPad3,         // KP_3
+	0xffb4:     wde.KeyPad4,         // KP_4
+	0xffb5:     wde.KeyPad5,         // KP_5
+	0xffb6:     wde.KeyPad6,         // KP_6
+	0xffb7:     wde.KeyPad7,         // KP_7
+	0xffb8:     wde.KeyPad8,         // KP_8
+	0xffb9:     wde.KeyPad9,         // KP_9
+	0xffce:     wde.KeyF17,          // F17
+	0xffcf:     wde.KeyF18,          // F18
+	0xffd0:     wde.KeyF19,          // F19
+	0xffd1:     wde.KeyF20,          // F20
+	0xffd2:     wde.KeyF21,          // F21
+	0xffd3:     wde.KeyF22,          // F22
+	0xffd4:     wde.KeyF23,          // F23
+	0xffd5:     wde.KeyF24,          // F24
+	0xff13:     wde.KeyPause,        // Pause
+	0xff61:     wde.KeyPrintScreen,  // Print
+	0xff14:     wde.KeyScrollLock,   // Scroll_Lock
+	0xff67:     wde.KeyMenu,         // Menu
+	0x1008ff12: wde.KeyMute,         // XF86AudioMute
+	0x1008ff11: wde.KeyVolumeDown,   // XF86AudioLowerVolume
+	0x1008ff13: wde.KeyVolumeUp,     // XF86AudioRaiseVolume
+	0x1008ff14: wde.KeyMediaPlay,    // XF86AudioPlay
+	0x1008ff15: wde.KeyMediaStop,    // XF86AudioStop
+	0x1008ff16: wde.KeyMediaPrev,    // XF86AudioPrev
+	0x1008ff17: wde.KeyMediaNext,    // XF86AudioNext
+}
+
+// physicalKey is what is known about a key from its keycode alone.
+type physicalKey struct {
+	scancode wde.Scancode
+	// key is the key in the same place on a US keyboard
+	key string
+}
 
+// physicalKeys maps the keycodes of the evdev and kbd drivers, which are
+// the Linux key codes plus 8, to the keys they belong to.
+var physicalKeys = map[xproto.Keycode]physicalKey{
+	9:   {wde.ScancodeEscape, wde.KeyEscape},
+	10:  {wde.Scancode1, wde.Key1},
+	11:  {wde.Scancode2, wde.Key2},
+	12:  {wde.Scancode3, wde.Key3},
+	13:  {wde.Scancode4, wde.Key4},
+	14:  {wde.Scancode5, wde.Key5},
+	15:  {wde.Scancode6, wde.Key6},
+	16:  {wde.Scancode7, wde.Key7},
+	17:  {wde.Scancode8, wde.Key8},
+	18:  {wde.Scancode9, wde.Key9},
+	19:  {wde.Scancode0, wde.Key0},
+	20:  {wde.ScancodeMinus, wde.KeyMinus},
+	21:  {wde.ScancodeEqual, wde.KeyEqual},
+	22:  {wde.ScancodeBackspace, wde.KeyBackspace},
+	23:  {wde.ScancodeTab, wde.KeyTab},
+	24:  {wde.ScancodeQ, wde.KeyQ},
+	25:  {wde.ScancodeW, wde.KeyW},
+	26:  {wde.ScancodeE, wde.KeyE},
+	27:  {wde.ScancodeR, wde.KeyR},
+	28:  {wde.ScancodeT, wde.KeyT},
+	29:  {wde.ScancodeY, wde.KeyY},
+	30:  {wde.ScancodeU, wde.KeyU},
+	31:  {wde.ScancodeI, wde.KeyI},
+	32:  {wde.ScancodeO, wde.KeyO},
+	33:  {wde.ScancodeP, wde.KeyP},
+	34:  {wde.ScancodeLeftBracket, wde.KeyLeftBracket},
+	35:  {wde.ScancodeRightBracket, wde.KeyRightBracket},
+	36:  {wde.ScancodeReturn, wde.KeyReturn},
+	37:  {wde.ScancodeLeftControl, wde.KeyLeftControl},
+	38:  {wde.ScancodeA, wde.KeyA},
+	39:  {wde.ScancodeS, wde.KeyS},
+	40:  {wde.ScancodeD, wde.KeyD},
+	41:  {wde.ScancodeF, wde.KeyF},
+	42:  {wde.ScancodeG, wde.KeyG},
+	43:  {wde.ScancodeH, wde.KeyH},
+	44:  {wde.ScancodeJ, wde.KeyJ},
+	45:  {wde.ScancodeK, wde.KeyK},
+	46:  {wde.ScancodeL, wde.KeyL},
+	47:  {wde.ScancodeSemicolon, wde.KeySemicolon},
+	48:  {wde.ScancodeQuote, wde.KeyQuote},
+	49:  {wde.ScancodeBackTick, wde.KeyBackTick},
+	50:  {wde.ScancodeLeftShift, wde.KeyLeftShift},
+	51:  {wde.ScancodeBackslash, wde.KeyBackslash},
+	52:  {wde.ScancodeZ, wde.KeyZ},
+	53:  {wde.ScancodeX, wde.KeyX},
+	54:  {wde.ScancodeC, wde.KeyC},
+	55:  {wde.ScancodeV, wde.KeyV},
+	56:  {wde.ScancodeB, wde.KeyB},
+	57:  {wde.ScancodeN, wde.KeyN},
+	58:  {wde.ScancodeM, wde.KeyM},
+	59:  {wde.ScancodeComma, wde.KeyComma},
+	60:  {wde.ScancodePeriod, wde.KeyPeriod},
+	61:  {wde.ScancodeSlash, wde.KeySlash},
+	62:  {wde.ScancodeRightShift, wde.KeyRightShift},
+	63:  {wde.ScancodePadStar, wde.KeyPadStar},
+	64:  {wde.ScancodeLeftAlt, wde.KeyLeftAlt},
+	65:  {wde.ScancodeSpace, wde.KeySpace},
+	66:  {wde.ScancodeCapsLock, wde.KeyCapsLock},
+	67:  {wde.ScancodeF1, wde.KeyF1},
+	68:  {wde.ScancodeF2, wde.KeyF2},
+	69:  {wde.ScancodeF3, wde.KeyF3},
+	70:  {wde.ScancodeF4, wde.KeyF4},
+	71:  {wde.ScancodeF5, wde.KeyF5},
+	72:  {wde.ScancodeF6, wde.KeyF6},
+	73:  {wde.ScancodeF7, wde.KeyF7},
+	74:  {wde.ScancodeF8, wde.KeyF8},
+	75:  {wde.ScancodeF9, wde.KeyF9},
+	76:  {wde.ScancodeF10, wde.KeyF10},
+	77:  {wde.ScancodeNumlock, wde.KeyNumlock},
+	78:  {wde.ScancodeScrollLock, wde.KeyScrollLock},
+	79:  {wde.ScancodePad7, wde.KeyPadHome},
+	80:  {wde.ScancodePad8, wde.KeyPadUp},
+	81:  {wde.ScancodePad9, wde.KeyPadPrior},
+	82:  {wde.ScancodePadMinus, wde.KeyPadMinus},
+	83:  {wde.ScancodePad4, wde.KeyPadLeft},
+	84:  {wde.ScancodePad5, wde.KeyPadBegin},
+	85:  {wde.ScancodePad6, wde.KeyPadRight},
+	86:  {wde.ScancodePadPlus, wde.KeyPadPlus},
+	87:  {wde.ScancodePad1, wde.KeyPadEnd},
+	88:  {wde.ScancodePad2, wde.KeyPadDown},
+	89:  {wde.ScancodePad3, wde.KeyPadNext},
+	90:  {wde.ScancodePad0, wde.KeyPadInsert},
+	91:  {wde.ScancodePadDot, wde.KeyPadDelete},
+	94:  {wde.ScancodeNonUSBackslash, wde.KeyIntlBackslash},
+	95:  {wde.ScancodeF11, wde.KeyF11},
+	96:  {wde.ScancodeF12, wde.KeyF12},
+	104: {wde.ScancodePadEnter, wde.KeyPadEnter},
+	105: {wde.ScancodeRightControl, wde.KeyRightControl},
+	106: {wde.ScancodePadSlash, wde.KeyPadSlash},
+	107: {wde.ScancodePrintScreen, wde.KeyPrintScreen},
+	108: {wde.ScancodeRightAlt, wde.KeyRightAlt},
+	110: {wde.ScancodeHome, wde.KeyHome},
+	111: {wde.ScancodeUpArrow, wde.KeyUpArrow},
+	112: {wde.ScancodePrior, wde.KeyPrior},
+	113: {wde.ScancodeLeftArrow, wde.KeyLeftArrow},
+	114: {wde.ScancodeRightArrow, wde.KeyRightArrow},
+	115: {wde.ScancodeEnd, wde.KeyEnd},
+	116: {wde.ScancodeDownArrow, wde.KeyDownArrow},
+	117: {wde.ScancodeNext, wde.KeyNext},
+	118: {wde.ScancodeInsert, wde.KeyInsert},
+	119: {wde.ScancodeDelete, wde.KeyDelete},
+	121: {wde.ScancodeMute, wde.KeyMute},
+	122: {wde.ScancodeVolumeDown, wde.KeyVolumeDown},
+	123: {wde.ScancodeVolumeUp, wde.KeyVolumeUp},
+	125: {wde.ScancodePadEqual, wde.KeyPadEqual},
+	127: {wde.ScancodePause, wde.KeyPause},
+	133: {wde.ScancodeLeftSuper, wde.KeyLeftSuper},
+	134: {wde.ScancodeRightSuper, wde.KeyRightSuper},
+	135: {wde.ScancodeMenu, wde.KeyMenu},
+	171: {0, wde.KeyMediaNext},
+	172: {0, wde.KeyMediaPlay},
+	173: {0, wde.KeyMediaPrev},
+	174: {0, wde.KeyMediaStop},
+	191: {wde.ScancodeF13, wde.KeyF13},
+	192: {wde.ScancodeF14, wde.KeyF14},
+	193: {wde.ScancodeF15, wde.KeyF15},
+	194: {wde.ScancodeF16, wde.KeyF16},
+	195: {wde.ScancodeF17, wde.KeyF17},
+	196: {wde.ScancodeF18, wde.KeyF18},
+	197: {wde.ScancodeF19, wde.KeyF19},
+	198: {wde.ScancodeF20, wde.KeyF20},
+	199: {wde.ScancodeF21, wde.KeyF21},
+	200: {wde.ScancodeF22, wde.KeyF22},
+	201: {wde.ScancodeF23, wde.KeyF23},
+	202: {wde.ScancodeF24, wde.KeyF24},
 }
@@ -0,0 +1,67 @@
+/*
+   Copyright 2012 the go.wde authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package xgb
+
+import (
+	"github.com/BurntSushi/xgb/xproto"
+	"github.com/skelterjohn/go.wde"
+	"testing"
+)
+
+func TestKeysymRune(t *testing.T) {
+	for _, test := range []struct {
+		ks   xproto.Keysym
+		want rune
+	}{
+		{0x0061, 'a'},     // a
+		{0x00e9, 'é'},     // eacute
+		{0x01b3, 'ł'},     // lstroke
+		{0x03a2, 'ĸ'},     // kra
+		{0x04b1, 'ア'},     // kana_A
+		{0x05c7, 'ا'},     // Arabic_alef
+		{0x06c1, 'а'},     // Cyrillic_a
+		{0x06f1, 'Я'},     // Cyrillic_YA
+		{0x07c1, 'Α'},     // Greek_ALPHA
+		{0x07f9, 'ω'},     // Greek_omega
+		{0x0ce0, 'א'},     // hebrew_aleph
+		{0x0da1, 'ก'},     // Thai_kokai
+		{0x20ac, '€'},     // EuroSign
+		{0x01000430, 'а'}, // U0430
+		{0xffb5, '5'},     // KP_5
+		{0xffab, '+'},     // KP_Add
+		{0xff0d, 0},       // Return
+		{0xffe1, 0},       // Shift_L
+		{0xfe51, 0},       // dead_acute
+	} {
+		if got := keysymRune(test.ks); got != test.want {
+			t.Errorf("keysymRune(%#x) = %q, want %q", test.ks, got, test.want)
+		}
+	}
+}
+
+func TestKeyTablesKnown(t *testing.T) {
+	for ks, key := range keysymKeys {
+		if !wde.KnownKey(key) {
+			t.Errorf("keysymKeys[%#x] = %q, which is not a wde key", ks, key)
+		}
+	}
+	for code, pk := range physicalKeys {
+		if !wde.KnownKey(pk.key) {
+			t.Errorf("physicalKeys[%d].key = %q, which is not a wde key", code, pk.key)
+		}
+	}
+}
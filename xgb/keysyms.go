@@ -0,0 +1,748 @@
+/*
+   Copyright 2012 the go.wde authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package xgb
+
+import (
+	"github.com/BurntSushi/xgb/xproto"
+)
+
+// legacyRunes maps the keysyms from before X11 encoded Unicode directly,
+// outside Latin-1, to the characters they type. It is made from the
+// comments in X11/keysymdef.h, as xterm's keysym2ucs.c is.
+var legacyRunes = map[xproto.Keysym]rune{
+	0x01a1: 'Ą',    // Aogonek
+	0x01a2: '˘',    // breve
+	0x01a3: 'Ł',    // Lstroke
+	0x01a5: 'Ľ',    // Lcaron
+	0x01a6: 'Ś',    // Sacute
+	0x01a9: 'Š',    // Scaron
+	0x01aa: 'Ş',    // Scedilla
+	0x01ab: 'Ť',    // Tcaron
+	0x01ac: 'Ź',    // Zacute
+	0x01ae: 'Ž',    // Zcaron
+	0x01af: 'Ż',    // Zabovedot
+	0x01b1: 'ą',    // aogonek
+	0x01b2: '˛',    // ogonek
+	0x01b3: 'ł',    // lstroke
+	0x01b5: 'ľ',    // lcaron
+	0x01b6: 'ś',    // sacute
+	0x01b7: 'ˇ',    // caron
+	0x01b9: 'š',    // scaron
+	0x01ba: 'ş',    // scedilla
+	0x01bb: 'ť',    // tcaron
+	0x01bc: 'ź',    // zacute
+	0x01bd: '˝',    // doubleacute
+	0x01be: 'ž',    // zcaron
+	0x01bf: 'ż',    // zabovedot
+	0x01c0: 'Ŕ',    // Racute
+	0x01c3: 'Ă',    // Abreve
+	0x01c5: 'Ĺ',    // Lacute
+	0x01c6: 'Ć',    // Cacute
+	0x01c8: 'Č',    // Ccaron
+	0x01ca: 'Ę',    // Eogonek
+	0x01cc: 'Ě',    // Ecaron
+	0x01cf: 'Ď',    // Dcaron
+	0x01d0: 'Đ',    // Dstroke
+	0x01d1: 'Ń',    // Nacute
+	0x01d2: 'Ň',    // Ncaron
+	0x01d5: 'Ő',    // Odoubleacute
+	0x01d8: 'Ř',    // Rcaron
+	0x01d9: 'Ů',    // Uring
+	0x01db: 'Ű',    // Udoubleacute
+	0x01de: 'Ţ',    // Tcedilla
+	0x01e0: 'ŕ',    // racute
+	0x01e3: 'ă',    // abreve
+	0x01e5: 'ĺ',    // lacute
+	0x01e6: 'ć',    // cacute
+	0x01e8: 'č',    // ccaron
+	0x01ea: 'ę',    // eogonek
+	0x01ec: 'ě',    // ecaron
+	0x01ef: 'ď',    // dcaron
+	0x01f0: 'đ',    // dstroke
+	0x01f1: 'ń',    // nacute
+	0x01f2: 'ň',    // ncaron
+	0x01f5: 'ő',    // odoubleacute
+	0x01f8: 'ř',    // rcaron
+	0x01f9: 'ů',    // uring
+	0x01fb: 'ű',    // udoubleacute
+	0x01fe: 'ţ',    // tcedilla
+	0x01ff: '˙',    // abovedot
+	0x02a1: 'Ħ',    // Hstroke
+	0x02a6: 'Ĥ',    // Hcircumflex
+	0x02a9: 'İ',    // Iabovedot
+	0x02ab: 'Ğ',    // Gbreve
+	0x02ac: 'Ĵ',    // Jcircumflex
+	0x02b1: 'ħ',    // hstroke
+	0x02b6: 'ĥ',    // hcircumflex
+	0x02b9: 'ı',    // idotless
+	0x02bb: 'ğ',    // gbreve
+	0x02bc: 'ĵ',    // jcircumflex
+	0x02c5: 'Ċ',    // Cabovedot
+	0x02c6: 'Ĉ',    // Ccircumflex
+	0x02d5: 'Ġ',    // Gabovedot
+	0x02d8: 'Ĝ',    // Gcircumflex
+	0x02dd: 'Ŭ',    // Ubreve
+	0x02de: 'Ŝ',    // Scircumflex
+	0x02e5: 'ċ',    // cabovedot
+	0x02e6: 'ĉ',    // ccircumflex
+	0x02f5: 'ġ',    // gabovedot
+	0x02f8: 'ĝ',    // gcircumflex
+	0x02fd: 'ŭ',    // ubreve
+	0x02fe: 'ŝ',    // scircumflex
+	0x03a2: 'ĸ',    // kra
+	0x03a3: 'Ŗ',    // Rcedilla
+	0x03a5: 'Ĩ',    // Itilde
+	0x03a6: 'Ļ',    // Lcedilla
+	0x03aa: 'Ē',    // Emacron
+	0x03ab: 'Ģ',    // Gcedilla
+	0x03ac: 'Ŧ',    // Tslash
+	0x03b3: 'ŗ',    // rcedilla
+	0x03b5: 'ĩ',    // itilde
+	0x03b6: 'ļ',    // lcedilla
+	0x03ba: 'ē',    // emacron
+	0x03bb: 'ģ',    // gcedilla
+	0x03bc: 'ŧ',    // tslash
+	0x03bd: 'Ŋ',    // ENG
+	0x03bf: 'ŋ',    // eng
+	0x03c0: 'Ā',    // Amacron
+	0x03c7: 'Į',    // Iogonek
+	0x03cc: 'Ė',    // Eabovedot
+	0x03cf: 'Ī',    // Imacron
+	0x03d1: 'Ņ',    // Ncedilla
+	0x03d2: 'Ō',    // Omacron
+	0x03d3: 'Ķ',    // Kcedilla
+	0x03d9: 'Ų',    // Uogonek
+	0x03dd: 'Ũ',    // Utilde
+	0x03de: 'Ū',    // Umacron
+	0x03e0: 'ā',    // amacron
+	0x03e7: 'į',    // iogonek
+	0x03ec: 'ė',    // eabovedot
+	0x03ef: 'ī',    // imacron
+	0x03f1: 'ņ',    // ncedilla
+	0x03f2: 'ō',    // omacron
+	0x03f3: 'ķ',    // kcedilla
+	0x03f9: 'ų',    // uogonek
+	0x03fd: 'ũ',    // utilde
+	0x03fe: 'ū',    // umacron
+	0x047e: '‾',    // overline
+	0x04a1: '。',    // kana_fullstop
+	0x04a2: '「',    // kana_openingbracket
+	0x04a3: '」',    // kana_closingbracket
+	0x04a4: '、',    // kana_comma
+	0x04a5: '・',    // kana_conjunctive
+	0x04a6: 'ヲ',    // kana_WO
+	0x04a7: 'ァ',    // kana_a
+	0x04a8: 'ィ',    // kana_i
+	0x04a9: 'ゥ',    // kana_u
+	0x04aa: 'ェ',    // kana_e
+	0x04ab: 'ォ',    // kana_o
+	0x04ac: 'ャ',    // kana_ya
+	0x04ad: 'ュ',    // kana_yu
+	0x04ae: 'ョ',    // kana_yo
+	0x04af: 'ッ',    // kana_tsu
+	0x04b0: 'ー',    // prolongedsound
+	0x04b1: 'ア',    // kana_A
+	0x04b2: 'イ',    // kana_I
+	0x04b3: 'ウ',    // kana_U
+	0x04b4: 'エ',    // kana_E
+	0x04b5: 'オ',    // kana_O
+	0x04b6: 'カ',    // kana_KA
+	0x04b7: 'キ',    // kana_KI
+	0x04b8: 'ク',    // kana_KU
+	0x04b9: 'ケ',    // kana_KE
+	0x04ba: 'コ',    // kana_KO
+	0x04bb: 'サ',    // kana_SA
+	0x04bc: 'シ',    // kana_SHI
+	0x04bd: 'ス',    // kana_SU
+	0x04be: 'セ',    // kana_SE
+	0x04bf: 'ソ',    // kana_SO
+	0x04c0: 'タ',    // kana_TA
+	0x04c1: 'チ',    // kana_CHI
+	0x04c2: 'ツ',    // kana_TSU
+	0x04c3: 'テ',    // kana_TE
+	0x04c4: 'ト',    // kana_TO
+	0x04c5: 'ナ',    // kana_NA
+	0x04c6: 'ニ',    // kana_NI
+	0x04c7: 'ヌ',    // kana_NU
+	0x04c8: 'ネ',    // kana_NE
+	0x04c9: 'ノ',    // kana_NO
+	0x04ca: 'ハ',    // kana_HA
+	0x04cb: 'ヒ',    // kana_HI
+	0x04cc: 'フ',    // kana_FU
+	0x04cd: 'ヘ',    // kana_HE
+	0x04ce: 'ホ',    // kana_HO
+	0x04cf: 'マ',    // kana_MA
+	0x04d0: 'ミ',    // kana_MI
+	0x04d1: 'ム',    // kana_MU
+	0x04d2: 'メ',    // kana_ME
+	0x04d3: 'モ',    // kana_MO
+	0x04d4: 'ヤ',    // kana_YA
+	0x04d5: 'ユ',    // kana_YU
+	0x04d6: 'ヨ',    // kana_YO
+	0x04d7: 'ラ',    // kana_RA
+	0x04d8: 'リ',    // kana_RI
+	0x04d9: 'ル',    // kana_RU
+	0x04da: 'レ',    // kana_RE
+	0x04db: 'ロ',    // kana_RO
+	0x04dc: 'ワ',    // kana_WA
+	0x04dd: 'ン',    // kana_N
+	0x04de: '゛',    // voicedsound
+	0x04df: '゜',    // semivoicedsound
+	0x05ac: '،',    // Arabic_comma
+	0x05bb: '؛',    // Arabic_semicolon
+	0x05bf: '؟',    // Arabic_question_mark
+	0x05c1: 'ء',    // Arabic_hamza
+	0x05c2: 'آ',    // Arabic_maddaonalef
+	0x05c3: 'أ',    // Arabic_hamzaonalef
+	0x05c4: 'ؤ',    // Arabic_hamzaonwaw
+	0x05c5: 'إ',    // Arabic_hamzaunderalef
+	0x05c6: 'ئ',    // Arabic_hamzaonyeh
+	0x05c7: 'ا',    // Arabic_alef
+	0x05c8: 'ب',    // Arabic_beh
+	0x05c9: 'ة',    // Arabic_tehmarbuta
+	0x05ca: 'ت',    // Arabic_teh
+	0x05cb: 'ث',    // Arabic_theh
+	0x05cc: 'ج',    // Arabic_jeem
+	0x05cd: 'ح',    // Arabic_hah
+	0x05ce: 'خ',    // Arabic_khah
+	0x05cf: 'د',    // Arabic_dal
+	0x05d0: 'ذ',    // Arabic_thal
+	0x05d1: 'ر',    // Arabic_ra
+	0x05d2: 'ز',    // Arabic_zain
+	0x05d3: 'س',    // Arabic_seen
+	0x05d4: 'ش',    // Arabic_sheen
+	0x05d5: 'ص',    // Arabic_sad
+	0x05d6: 'ض',    // Arabic_dad
+	0x05d7: 'ط',    // Arabic_tah
+	0x05d8: 'ظ',    // Arabic_zah
+	0x05d9: 'ع',    // Arabic_ain
+	0x05da: 'غ',    // Arabic_ghain
+	0x05e0: 'ـ',    // Arabic_tatweel
+	0x05e1: 'ف',    // Arabic_feh
+	0x05e2: 'ق',    // Arabic_qaf
+	0x05e3: 'ك',    // Arabic_kaf
+	0x05e4: 'ل',    // Arabic_lam
+	0x05e5: 'م',    // Arabic_meem
+	0x05e6: 'ن',    // Arabic_noon
+	0x05e7: 'ه',    // Arabic_ha
+	0x05e8: 'و',    // Arabic_waw
+	0x05e9: 'ى',    // Arabic_alefmaksura
+	0x05ea: 'ي',    // Arabic_yeh
+	0x05eb: 0x064b, // Arabic_fathatan
+	0x05ec: 0x064c, // Arabic_dammatan
+	0x05ed: 0x064d, // Arabic_kasratan
+	0x05ee: 0x064e, // Arabic_fatha
+	0x05ef: 0x064f, // Arabic_damma
+	0x05f0: 0x0650, // Arabic_kasra
+	0x05f1: 0x0651, // Arabic_shadda
+	0x05f2: 0x0652, // Arabic_sukun
+	0x06a1: 'ђ',    // Serbian_dje
+	0x06a2: 'ѓ',    // Macedonia_gje
+	0x06a3: 'ё',    // Cyrillic_io
+	0x06a4: 'є',    // Ukrainian_ie
+	0x06a5: 'ѕ',    // Macedonia_dse
+	0x06a6: 'і',    // Ukrainian_i
+	0x06a7: 'ї',    // Ukrainian_yi
+	0x06a8: 'ј',    // Cyrillic_je
+	0x06a9: 'љ',    // Cyrillic_lje
+	0x06aa: 'њ',    // Cyrillic_nje
+	0x06ab: 'ћ',    // Serbian_tshe
+	0x06ac: 'ќ',    // Macedonia_kje
+	0x06ad: 'ґ',    // Ukrainian_ghe_with_upturn
+	0x06ae: 'ў',    // Byelorussian_shortu
+	0x06af: 'џ',    // Cyrillic_dzhe
+	0x06b0: '№',    // numerosign
+	0x06b1: 'Ђ',    // Serbian_DJE
+	0x06b2: 'Ѓ',    // Macedonia_GJE
+	0x06b3: 'Ё',    // Cyrillic_IO
+	0x06b4: 'Є',    // Ukrainian_IE
+	0x06b5: 'Ѕ',    // Macedonia_DSE
+	0x06b6: 'І',    // Ukrainian_I
+	0x06b7: 'Ї',    // Ukrainian_YI
+	0x06b8: 'Ј',    // Cyrillic_JE
+	0x06b9: 'Љ',    // Cyrillic_LJE
+	0x06ba: 'Њ',    // Cyrillic_NJE
+	0x06bb: 'Ћ',    // Serbian_TSHE
+	0x06bc: 'Ќ',    // Macedonia_KJE
+	0x06bd: 'Ґ',    // Ukrainian_GHE_WITH_UPTURN
+	0x06be: 'Ў',    // Byelorussian_SHORTU
+	0x06bf: 'Џ',    // Cyrillic_DZHE
+	0x06c0: 'ю',    // Cyrillic_yu
+	0x06c1: 'а',    // Cyrillic_a
+	0x06c2: 'б',    // Cyrillic_be
+	0x06c3: 'ц',    // Cyrillic_tse
+	0x06c4: 'д',    // Cyrillic_de
+	0x06c5: 'е',    // Cyrillic_ie
+	0x06c6: 'ф',    // Cyrillic_ef
+	0x06c7: 'г',    // Cyrillic_ghe
+	0x06c8: 'х',    // Cyrillic_ha
+	0x06c9: 'и',    // Cyrillic_i
+	0x06ca: 'й',    // Cyrillic_shorti
+	0x06cb: 'к',    // Cyrillic_ka
+	0x06cc: 'л',    // Cyrillic_el
+	0x06cd: 'м',    // Cyrillic_em
+	0x06ce: 'н',    // Cyrillic_en
+	0x06cf: 'о',    // Cyrillic_o
+	0x06d0: 'п',    // Cyrillic_pe
+	0x06d1: 'я',    // Cyrillic_ya
+	0x06d2: 'р',    // Cyrillic_er
+	0x06d3: 'с',    // Cyrillic_es
+	0x06d4: 'т',    // Cyrillic_te
+	0x06d5: 'у',    // Cyrillic_u
+	0x06d6: 'ж',    // Cyrillic_zhe
+	0x06d7: 'в',    // Cyrillic_ve
+	0x06d8: 'ь',    // Cyrillic_softsign
+	0x06d9: 'ы',    // Cyrillic_yeru
+	0x06da: 'з',    // Cyrillic_ze
+	0x06db: 'ш',    // Cyrillic_sha
+	0x06dc: 'э',    // Cyrillic_e
+	0x06dd: 'щ',    // Cyrillic_shcha
+	0x06de: 'ч',    // Cyrillic_che
+	0x06df: 'ъ',    // Cyrillic_hardsign
+	0x06e0: 'Ю',    // Cyrillic_YU
+	0x06e1: 'А',    // Cyrillic_A
+	0x06e2: 'Б',    // Cyrillic_BE
+	0x06e3: 'Ц',    // Cyrillic_TSE
+	0x06e4: 'Д',    // Cyrillic_DE
+	0x06e5: 'Е',    // Cyrillic_IE
+	0x06e6: 'Ф',    // Cyrillic_EF
+	0x06e7: 'Г',    // Cyrillic_GHE
+	0x06e8: 'Х',    // Cyrillic_HA
+	0x06e9: 'И',    // Cyrillic_I
+	0x06ea: 'Й',    // Cyrillic_SHORTI
+	0x06eb: 'К',    // Cyrillic_KA
+	0x06ec: 'Л',    // Cyrillic_EL
+	0x06ed: 'М',    // Cyrillic_EM
+	0x06ee: 'Н',    // Cyrillic_EN
+	0x06ef: 'О',    // Cyrillic_O
+	0x06f0: 'П',    // Cyrillic_PE
+	0x06f1: 'Я',    // Cyrillic_YA
+	0x06f2: 'Р',    // Cyrillic_ER
+	0x06f3: 'С',    // Cyrillic_ES
+	0x06f4: 'Т',    // Cyrillic_TE
+	0x06f5: 'У',    // Cyrillic_U
+	0x06f6: 'Ж',    // Cyrillic_ZHE
+	0x06f7: 'В',    // Cyrillic_VE
+	0x06f8: 'Ь',    // Cyrillic_SOFTSIGN
+	0x06f9: 'Ы',    // Cyrillic_YERU
+	0x06fa: 'З',    // Cyrillic_ZE
+	0x06fb: 'Ш',    // Cyrillic_SHA
+	0x06fc: 'Э',    // Cyrillic_E
+	0x06fd: 'Щ',    // Cyrillic_SHCHA
+	0x06fe: 'Ч',    // Cyrillic_CHE
+	0x06ff: 'Ъ',    // Cyrillic_HARDSIGN
+	0x07a1: 'Ά',    // Greek_ALPHAaccent
+	0x07a2: 'Έ',    // Greek_EPSILONaccent
+	0x07a3: 'Ή',    // Greek_ETAaccent
+	0x07a4: 'Ί',    // Greek_IOTAaccent
+	0x07a5: 'Ϊ',    // Greek_IOTAdieresis
+	0x07a7: 'Ό',    // Greek_OMICRONaccent
+	0x07a8: 'Ύ',    // Greek_UPSILONaccent
+	0x07a9: 'Ϋ',    // Greek_UPSILONdieresis
+	0x07ab: 'Ώ',    // Greek_OMEGAaccent
+	0x07ae: '΅',    // Greek_accentdieresis
+	0x07af: '―',    // Greek_horizbar
+	0x07b1: 'ά',    // Greek_alphaaccent
+	0x07b2: 'έ',    // Greek_epsilonaccent
+	0x07b3: 'ή',    // Greek_etaaccent
+	0x07b4: 'ί',    // Greek_iotaaccent
+	0x07b5: 'ϊ',    // Greek_iotadieresis
+	0x07b6: 'ΐ',    // Greek_iotaaccentdieresis
+	0x07b7: 'ό',    // Greek_omicronaccent
+	0x07b8: 'ύ',    // Greek_upsilonaccent
+	0x07b9: 'ϋ',    // Greek_upsilondieresis
+	0x07ba: 'ΰ',    // Greek_upsilonaccentdieresis
+	0x07bb: 'ώ',    // Greek_omegaaccent
+	0x07c1: 'Α',    // Greek_ALPHA
+	0x07c2: 'Β',    // Greek_BETA
+	0x07c3: 'Γ',    // Greek_GAMMA
+	0x07c4: 'Δ',    // Greek_DELTA
+	0x07c5: 'Ε',    // Greek_EPSILON
+	0x07c6: 'Ζ',    // Greek_ZETA
+	0x07c7: 'Η',    // Greek_ETA
+	0x07c8: 'Θ',    // Greek_THETA
+	0x07c9: 'Ι',    // Greek_IOTA
+	0x07ca: 'Κ',    // Greek_KAPPA
+	0x07cb: 'Λ',    // Greek_LAMDA
+	0x07cc: 'Μ',    // Greek_MU
+	0x07cd: 'Ν',    // Greek_NU
+	0x07ce: 'Ξ',    // Greek_XI
+	0x07cf: 'Ο',    // Greek_OMICRON
+	0x07d0: 'Π',    // Greek_PI
+	0x07d1: 'Ρ',    // Greek_RHO
+	0x07d2: 'Σ',    // Greek_SIGMA
+	0x07d4: 'Τ',    // Greek_TAU
+	0x07d5: 'Υ',    // Greek_UPSILON
+	0x07d6: 'Φ',    // Greek_PHI
+	0x07d7: 'Χ',    // Greek_CHI
+	0x07d8: 'Ψ',    // Greek_PSI
+	0x07d9: 'Ω',    // Greek_OMEGA
+	0x07e1: 'α',    // Greek_alpha
+	0x07e2: 'β',    // Greek_beta
+	0x07e3: 'γ',    // Greek_gamma
+	0x07e4: 'δ',    // Greek_delta
+	0x07e5: 'ε',    // Greek_epsilon
+	0x07e6: 'ζ',    // Greek_zeta
+	0x07e7: 'η',    // Greek_eta
+	0x07e8: 'θ',    // Greek_theta
+	0x07e9: 'ι',    // Greek_iota
+	0x07ea: 'κ',    // Greek_kappa
+	0x07eb: 'λ',    // Greek_lamda
+	0x07ec: 'μ',    // Greek_mu
+	0x07ed: 'ν',    // Greek_nu
+	0x07ee: 'ξ',    // Greek_xi
+	0x07ef: 'ο',    // Greek_omicron
+	0x07f0: 'π',    // Greek_pi
+	0x07f1: 'ρ',    // Greek_rho
+	0x07f2: 'σ',    // Greek_sigma
+	0x07f3: 'ς',    // Greek_finalsmallsigma
+	0x07f4: 'τ',    // Greek_tau
+	0x07f5: 'υ',    // Greek_upsilon
+	0x07f6: 'φ',    // Greek_phi
+	0x07f7: 'χ',    // Greek_chi
+	0x07f8: 'ψ',    // Greek_psi
+	0x07f9: 'ω',    // Greek_omega
+	0x08a1: '⎷',    // leftradical
+	0x08a4: '⌠',    // topintegral
+	0x08a5: '⌡',    // botintegral
+	0x08a7: '⎡',    // topleftsqbracket
+	0x08a8: '⎣',    // botleftsqbracket
+	0x08a9: '⎤',    // toprightsqbracket
+	0x08aa: '⎦',    // botrightsqbracket
+	0x08ab: '⎛',    // topleftparens
+	0x08ac: '⎝',    // botleftparens
+	0x08ad: '⎞',    // toprightparens
+	0x08ae: '⎠',    // botrightparens
+	0x08af: '⎨',    // leftmiddlecurlybrace
+	0x08b0: '⎬',    // rightmiddlecurlybrace
+	0x08bc: '≤',    // lessthanequal
+	0x08bd: '≠',    // notequal
+	0x08be: '≥',    // greaterthanequal
+	0x08bf: '∫',    // integral
+	0x08c0: '∴',    // therefore
+	0x08c1: '∝',    // variation
+	0x08c2: '∞',    // infinity
+	0x08c5: '∇',    // nabla
+	0x08c8: '∼',    // approximate
+	0x08c9: '≃',    // similarequal
+	0x08cd: '⇔',    // ifonlyif
+	0x08ce: '⇒',    // implies
+	0x08cf: '≡',    // identical
+	0x08d6: '√',    // radical
+	0x08da: '⊂',    // includedin
+	0x08db: '⊃',    // includes
+	0x08dc: '∩',    // intersection
+	0x08dd: '∪',    // union
+	0x08de: '∧',    // logicaland
+	0x08df: '∨',    // logicalor
+	0x08ef: '∂',    // partialderivative
+	0x08f6: 'ƒ',    // function
+	0x08fb: '←',    // leftarrow
+	0x08fc: '↑',    // uparrow
+	0x08fd: '→',    // rightarrow
+	0x08fe: '↓',    // downarrow
+	0x09e0: '◆',    // soliddiamond
+	0x09e1: '▒',    // checkerboard
+	0x09e2: '␉',    // ht
+	0x09e3: '␌',    // ff
+	0x09e4: '␍',    // cr
+	0x09e5: '␊',    // lf
+	0x09e8: '␤',    // nl
+	0x09e9: '␋',    // vt
+	0x09ea: '┘',    // lowrightcorner
+	0x09eb: '┐',    // uprightcorner
+	0x09ec: '┌',    // upleftcorner
+	0x09ed: '└',    // lowleftcorner
+	0x09ee: '┼',    // crossinglines
+	0x09ef: '⎺',    // horizlinescan1
+	0x09f0: '⎻',    // horizlinescan3
+	0x09f1: '─',    // horizlinescan5
+	0x09f2: '⎼',    // horizlinescan7
+	0x09f3: '⎽',    // horizlinescan9
+	0x09f4: '├',    // leftt
+	0x09f5: '┤',    // rightt
+	0x09f6: '┴',    // bott
+	0x09f7: '┬',    // topt
+	0x09f8: '│',    // vertbar
+	0x0aa1: 0x2003, // emspace
+	0x0aa2: 0x2002, // enspace
+	0x0aa3: 0x2004, // em3space
+	0x0aa4: 0x2005, // em4space
+	0x0aa5: 0x2007, // digitspace
+	0x0aa6: 0x2008, // punctspace
+	0x0aa7: 0x2009, // thinspace
+	0x0aa8: 0x200a, // hairspace
+	0x0aa9: '—',    // emdash
+	0x0aaa: '–',    // endash
+	0x0aae: '…',    // ellipsis
+	0x0aaf: '‥',    // doubbaselinedot
+	0x0ab0: '⅓',    // onethird
+	0x0ab1: '⅔',    // twothirds
+	0x0ab2: '⅕',    // onefifth
+	0x0ab3: '⅖',    // twofifths
+	0x0ab4: '⅗',    // threefifths
+	0x0ab5: '⅘',    // fourfifths
+	0x0ab6: '⅙',    // onesixth
+	0x0ab7: '⅚',    // fivesixths
+	0x0ab8: '℅',    // careof
+	0x0abb: '‒',    // figdash
+	0x0ac3: '⅛',    // oneeighth
+	0x0ac4: '⅜',    // threeeighths
+	0x0ac5: '⅝',    // fiveeighths
+	0x0ac6: '⅞',    // seveneighths
+	0x0ac9: '™',    // trademark
+	0x0ad0: '‘',    // leftsinglequotemark
+	0x0ad1: '’',    // rightsinglequotemark
+	0x0ad2: '“',    // leftdoublequotemark
+	0x0ad3: '”',    // rightdoublequotemark
+	0x0ad4: '℞',    // prescription
+	0x0ad5: '‰',    // permille
+	0x0ad6: '′',    // minutes
+	0x0ad7: '″',    // seconds
+	0x0ad9: '✝',    // latincross
+	0x0aec: '♣',    // club
+	0x0aed: '♦',    // diamond
+	0x0aee: '♥',    // heart
+	0x0af0: '✠',    // maltesecross
+	0x0af1: '†',    // dagger
+	0x0af2: '‡',    // doubledagger
+	0x0af3: '✓',    // checkmark
+	0x0af4: '✗',    // ballotcross
+	0x0af5: '♯',    // musicalsharp
+	0x0af6: '♭',    // musicalflat
+	0x0af7: '♂',    // malesymbol
+	0x0af8: '♀',    // femalesymbol
+	0x0af9: '☎',    // telephone
+	0x0afa: '⌕',    // telephonerecorder
+	0x0afb: '℗',    // phonographcopyright
+	0x0afc: '‸',    // caret
+	0x0afd: '‚',    // singlelowquotemark
+	0x0afe: '„',    // doublelowquotemark
+	0x0bc2: '⊤',    // downtack
+	0x0bc4: '⌊',    // downstile
+	0x0bca: '∘',    // jot
+	0x0bcc: '⎕',    // quad
+	0x0bce: '⊥',    // uptack
+	0x0bcf: '○',    // circle
+	0x0bd3: '⌈',    // upstile
+	0x0bdc: '⊣',    // lefttack
+	0x0bfc: '⊢',    // righttack
+	0x0cdf: '‗',    // hebrew_doublelowline
+	0x0ce0: 'א',    // hebrew_aleph
+	0x0ce1: 'ב',    // hebrew_bet
+	0x0ce2: 'ג',    // hebrew_gimel
+	0x0ce3: 'ד',    // hebrew_dalet
+	0x0ce4: 'ה',    // hebrew_he
+	0x0ce5: 'ו',    // hebrew_waw
+	0x0ce6: 'ז',    // hebrew_zain
+	0x0ce7: 'ח',    // hebrew_chet
+	0x0ce8: 'ט',    // hebrew_tet
+	0x0ce9: 'י',    // hebrew_yod
+	0x0cea: 'ך',    // hebrew_finalkaph
+	0x0ceb: 'כ',    // hebrew_kaph
+	0x0cec: 'ל',    // hebrew_lamed
+	0x0ced: 'ם',    // hebrew_finalmem
+	0x0cee: 'מ',    // hebrew_mem
+	0x0cef: 'ן',    // hebrew_finalnun
+	0x0cf0: 'נ',    // hebrew_nun
+	0x0cf1: 'ס',    // hebrew_samech
+	0x0cf2: 'ע',    // hebrew_ayin
+	0x0cf3: 'ף',    // hebrew_finalpe
+	0x0cf4: 'פ',    // hebrew_pe
+	0x0cf5: 'ץ',    // hebrew_finalzade
+	0x0cf6: 'צ',    // hebrew_zade
+	0x0cf7: 'ק',    // hebrew_qoph
+	0x0cf8: 'ר',    // hebrew_resh
+	0x0cf9: 'ש',    // hebrew_shin
+	0x0cfa: 'ת',    // hebrew_taw
+	0x0da1: 'ก',    // Thai_kokai
+	0x0da2: 'ข',    // Thai_khokhai
+	0x0da3: 'ฃ',    // Thai_khokhuat
+	0x0da4: 'ค',    // Thai_khokhwai
+	0x0da5: 'ฅ',    // Thai_khokhon
+	0x0da6: 'ฆ',    // Thai_khorakhang
+	0x0da7: 'ง',    // Thai_ngongu
+	0x0da8: 'จ',    // Thai_chochan
+	0x0da9: 'ฉ',    // Thai_choching
+	0x0daa: 'ช',    // Thai_chochang
+	0x0dab: 'ซ',    // Thai_soso
+	0x0dac: 'ฌ',    // Thai_chochoe
+	0x0dad: 'ญ',    // Thai_yoying
+	0x0dae: 'ฎ',    // Thai_dochada
+	0x0daf: 'ฏ',    // Thai_topatak
+	0x0db0: 'ฐ',    // Thai_thothan
+	0x0db1: 'ฑ',    // Thai_thonangmontho
+	0x0db2: 'ฒ',    // Thai_thophuthao
+	0x0db3: 'ณ',    // Thai_nonen
+	0x0db4: 'ด',    // Thai_dodek
+	0x0db5: 'ต',    // Thai_totao
+	0x0db6: 'ถ',    // Thai_thothung
+	0x0db7: 'ท',    // Thai_thothahan
+	0x0db8: 'ธ',    // Thai_thothong
+	0x0db9: 'น',    // Thai_nonu
+	0x0dba: 'บ',    // Thai_bobaimai
+	0x0dbb: 'ป',    // Thai_popla
+	0x0dbc: 'ผ',    // Thai_phophung
+	0x0dbd: 'ฝ',    // Thai_fofa
+	0x0dbe: 'พ',    // Thai_phophan
+	0x0dbf: 'ฟ',    // Thai_fofan
+	0x0dc0: 'ภ',    // Thai_phosamphao
+	0x0dc1: 'ม',    // Thai_moma
+	0x0dc2: 'ย',    // Thai_yoyak
+	0x0dc3: 'ร',    // Thai_rorua
+	0x0dc4: 'ฤ',    // Thai_ru
+	0x0dc5: 'ล',    // Thai_loling
+	0x0dc6: 'ฦ',    // Thai_lu
+	0x0dc7: 'ว',    // Thai_wowaen
+	0x0dc8: 'ศ',    // Thai_sosala
+	0x0dc9: 'ษ',    // Thai_sorusi
+	0x0dca: 'ส',    // Thai_sosua
+	0x0dcb: 'ห',    // Thai_hohip
+	0x0dcc: 'ฬ',    // Thai_lochula
+	0x0dcd: 'อ',    // Thai_oang
+	0x0dce: 'ฮ',    // Thai_honokhuk
+	0x0dcf: 'ฯ',    // Thai_paiyannoi
+	0x0dd0: 'ะ',    // Thai_saraa
+	0x0dd1: 0x0e31, // Thai_maihanakat
+	0x0dd2: 'า',    // Thai_saraaa
+	0x0dd3: 'ำ',    // Thai_saraam
+	0x0dd4: 0x0e34, // Thai_sarai
+	0x0dd5: 0x0e35, // Thai_saraii
+	0x0dd6: 0x0e36, // Thai_saraue
+	0x0dd7: 0x0e37, // Thai_sarauee
+	0x0dd8: 0x0e38, // Thai_sarau
+	0x0dd9: 0x0e39, // Thai_sarauu
+	0x0dda: 0x0e3a, // Thai_phinthu
+	0x0ddf: '฿',    // Thai_baht
+	0x0de0: 'เ',    // Thai_sarae
+	0x0de1: 'แ',    // Thai_saraae
+	0x0de2: 'โ',    // Thai_sarao
+	0x0de3: 'ใ',    // Thai_saraaimaimuan
+	0x0de4: 'ไ',    // Thai_saraaimaimalai
+	0x0de5: 'ๅ',    // Thai_lakkhangyao
+	0x0de6: 'ๆ',    // Thai_maiyamok
+	0x0de7: 0x0e47, // Thai_maitaikhu
+	0x0de8: 0x0e48, // Thai_maiek
+	0x0de9: 0x0e49, // Thai_maitho
+	0x0dea: 0x0e4a, // Thai_maitri
+	0x0deb: 0x0e4b, // Thai_maichattawa
+	0x0dec: 0x0e4c, // Thai_thanthakhat
+	0x0ded: 0x0e4d, // Thai_nikhahit
+	0x0df0: '๐',    // Thai_leksun
+	0x0df1: '๑',    // Thai_leknung
+	0x0df2: '๒',    // Thai_leksong
+	0x0df3: '๓',    // Thai_leksam
+	0x0df4: '๔',    // Thai_leksi
+	0x0df5: '๕',    // Thai_lekha
+	0x0df6: '๖',    // Thai_lekhok
+	0x0df7: '๗',    // Thai_lekchet
+	0x0df8: '๘',    // Thai_lekpaet
+	0x0df9: '๙',    // Thai_lekkao
+	0x0ea1: 'ㄱ',    // Hangul_Kiyeog
+	0x0ea2: 'ㄲ',    // Hangul_SsangKiyeog
+	0x0ea3: 'ㄳ',    // Hangul_KiyeogSios
+	0x0ea4: 'ㄴ',    // Hangul_Nieun
+	0x0ea5: 'ㄵ',    // Hangul_NieunJieuj
+	0x0ea6: 'ㄶ',    // Hangul_NieunHieuh
+	0x0ea7: 'ㄷ',    // Hangul_Dikeud
+	0x0ea8: 'ㄸ',    // Hangul_SsangDikeud
+	0x0ea9: 'ㄹ',    // Hangul_Rieul
+	0x0eaa: 'ㄺ',    // Hangul_RieulKiyeog
+	0x0eab: 'ㄻ',    // Hangul_RieulMieum
+	0x0eac: 'ㄼ',    // Hangul_RieulPieub
+	0x0ead: 'ㄽ',    // Hangul_RieulSios
+	0x0eae: 'ㄾ',    // Hangul_RieulTieut
+	0x0eaf: 'ㄿ',    // Hangul_RieulPhieuf
+	0x0eb0: 'ㅀ',    // Hangul_RieulHieuh
+	0x0eb1: 'ㅁ',    // Hangul_Mieum
+	0x0eb2: 'ㅂ',    // Hangul_Pieub
+	0x0eb3: 'ㅃ',    // Hangul_SsangPieub
+	0x0eb4: 'ㅄ',    // Hangul_PieubSios
+	0x0eb5: 'ㅅ',    // Hangul_Sios
+	0x0eb6: 'ㅆ',    // Hangul_SsangSios
+	0x0eb7: 'ㅇ',    // Hangul_Ieung
+	0x0eb8: 'ㅈ',    // Hangul_Jieuj
+	0x0eb9: 'ㅉ',    // Hangul_SsangJieuj
+	0x0eba: 'ㅊ',    // Hangul_Cieuc
+	0x0ebb: 'ㅋ',    // Hangul_Khieuq
+	0x0ebc: 'ㅌ',    // Hangul_Tieut
+	0x0ebd: 'ㅍ',    // Hangul_Phieuf
+	0x0ebe: 'ㅎ',    // Hangul_Hieuh
+	0x0ebf: 'ㅏ',    // Hangul_A
+	0x0ec0: 'ㅐ',    // Hangul_AE
+	0x0ec1: 'ㅑ',    // Hangul_YA
+	0x0ec2: 'ㅒ',    // Hangul_YAE
+	0x0ec3: 'ㅓ',    // Hangul_EO
+	0x0ec4: 'ㅔ',    // Hangul_E
+	0x0ec5: 'ㅕ',    // Hangul_YEO
+	0x0ec6: 'ㅖ',    // Hangul_YE
+	0x0ec7: 'ㅗ',    // Hangul_O
+	0x0ec8: 'ㅘ',    // Hangul_WA
+	0x0ec9: 'ㅙ',    // Hangul_WAE
+	0x0eca: 'ㅚ',    // Hangul_OE
+	0x0ecb: 'ㅛ',    // Hangul_YO
+	0x0ecc: 'ㅜ',    // Hangul_U
+	0x0ecd: 'ㅝ',    // Hangul_WEO
+	0x0ece: 'ㅞ',    // Hangul_WE
+	0x0ecf: 'ㅟ',    // Hangul_WI
+	0x0ed0: 'ㅠ',    // Hangul_YU
+	0x0ed1: 'ㅡ',    // Hangul_EU
+	0x0ed2: 'ㅢ',    // Hangul_YI
+	0x0ed3: 'ㅣ',    // Hangul_I
+	0x0ed4: 'ᆨ',    // Hangul_J_Kiyeog
+	0x0ed5: 'ᆩ',    // Hangul_J_SsangKiyeog
+	0x0ed6: 'ᆪ',    // Hangul_J_KiyeogSios
+	0x0ed7: 'ᆫ',    // Hangul_J_Nieun
+	0x0ed8: 'ᆬ',    // Hangul_J_NieunJieuj
+	0x0ed9: 'ᆭ',    // Hangul_J_NieunHieuh
+	0x0eda: 'ᆮ',    // Hangul_J_Dikeud
+	0x0edb: 'ᆯ',    // Hangul_J_Rieul
+	0x0edc: 'ᆰ',    // Hangul_J_RieulKiyeog
+	0x0edd: 'ᆱ',    // Hangul_J_RieulMieum
+	0x0ede: 'ᆲ',    // Hangul_J_RieulPieub
+	0x0edf: 'ᆳ',    // Hangul_J_RieulSios
+	0x0ee0: 'ᆴ',    // Hangul_J_RieulTieut
+	0x0ee1: 'ᆵ',    // Hangul_J_RieulPhieuf
+	0x0ee2: 'ᆶ',    // Hangul_J_RieulHieuh
+	0x0ee3: 'ᆷ',    // Hangul_J_Mieum
+	0x0ee4: 'ᆸ',    // Hangul_J_Pieub
+	0x0ee5: 'ᆹ',    // Hangul_J_PieubSios
+	0x0ee6: 'ᆺ',    // Hangul_J_Sios
+	0x0ee7: 'ᆻ',    // Hangul_J_SsangSios
+	0x0ee8: 'ᆼ',    // Hangul_J_Ieung
+	0x0ee9: 'ᆽ',    // Hangul_J_Jieuj
+	0x0eea: 'ᆾ',    // Hangul_J_Cieuc
+	0x0eeb: 'ᆿ',    // Hangul_J_Khieuq
+	0x0eec: 'ᇀ',    // Hangul_J_Tieut
+	0x0eed: 'ᇁ',    // Hangul_J_Phieuf
+	0x0eee: 'ᇂ',    // Hangul_J_Hieuh
+	0x0eef: 'ㅭ',    // Hangul_RieulYeorinHieuh
+	0x0ef0: 'ㅱ',    // Hangul_SunkyeongeumMieum
+	0x0ef1: 'ㅸ',    // Hangul_SunkyeongeumPieub
+	0x0ef2: 'ㅿ',    // Hangul_PanSios
+	0x0ef3: 'ㆁ',    // Hangul_KkogjiDalrinIeung
+	0x0ef4: 'ㆄ',    // Hangul_SunkyeongeumPhieuf
+	0x0ef5: 'ㆆ',    // Hangul_YeorinHieuh
+	0x0ef6: 'ㆍ',    // Hangul_AraeA
+	0x0ef7: 'ㆎ',    // Hangul_AraeAE
+	0x0ef8: 'ᇫ',    // Hangul_J_PanSios
+	0x0ef9: 'ᇰ',    // Hangul_J_KkogjiDalrinIeung
+	0x0efa: 'ᇹ',    // Hangul_J_YeorinHieuh
+	0x13bc: 'Œ',    // OE
+	0x13bd: 'œ',    // oe
+	0x13be: 'Ÿ',    // Ydiaeresis
+}
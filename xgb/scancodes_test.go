@@ -0,0 +1,72 @@
+/*
+   Copyright 2012 the go.wde authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package xgb
+
+import (
+	"github.com/BurntSushi/xgb/xproto"
+	"github.com/skelterjohn/go.wde"
+	"testing"
+)
+
+func TestPhysicalKeys(t *testing.T) {
+	for _, test := range []struct {
+		code     xproto.Keycode
+		scancode wde.Scancode
+		key      string
+	}{
+		{9, wde.ScancodeEscape, wde.KeyEscape},
+		{10, wde.Scancode1, wde.Key1},
+		{19, wde.Scancode0, wde.Key0},
+		{24, wde.ScancodeQ, wde.KeyQ},
+		{36, wde.ScancodeReturn, wde.KeyReturn},
+		{38, wde.ScancodeA, wde.KeyA},
+		{49, wde.ScancodeBackTick, wde.KeyBackTick},
+		{50, wde.ScancodeLeftShift, wde.KeyLeftShift},
+		{65, wde.ScancodeSpace, wde.KeySpace},
+		{67, wde.ScancodeF1, wde.KeyF1},
+		{77, wde.ScancodeNumlock, wde.KeyNumlock},
+		{79, wde.ScancodePad7, wde.KeyPadHome},
+		{94, wde.ScancodeNonUSBackslash, wde.KeyIntlBackslash},
+		{96, wde.ScancodeF12, wde.KeyF12},
+		{104, wde.ScancodePadEnter, wde.KeyPadEnter},
+		{108, wde.ScancodeRightAlt, wde.KeyRightAlt},
+		{111, wde.ScancodeUpArrow, wde.KeyUpArrow},
+		{119, wde.ScancodeDelete, wde.KeyDelete},
+		{127, wde.ScancodePause, wde.KeyPause},
+		{133, wde.ScancodeLeftSuper, wde.KeyLeftSuper},
+		{135, wde.ScancodeMenu, wde.KeyMenu},
+		{8, 0, ""},
+	} {
+		pk := physicalKeys[test.code]
+		if pk.scancode != test.scancode || pk.key != test.key {
+			t.Errorf("physicalKeys[%d] = {%#x, %q}, want {%#x, %q}", test.code, pk.scancode, pk.key, test.scancode, test.key)
+		}
+	}
+}
+
+func TestPhysicalKeysDistinct(t *testing.T) {
+	seen := make(map[wde.Scancode]xproto.Keycode)
+	for code, pk := range physicalKeys {
+		if pk.scancode == 0 {
+			continue
+		}
+		if other, ok := seen[pk.scancode]; ok {
+			t.Errorf("keycodes %d and %d both map to scancode %#x", other, code, pk.scancode)
+		}
+		seen[pk.scancode] = code
+	}
+}
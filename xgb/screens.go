@@ -0,0 +1,205 @@
+/*
+   Copyright 2012 the go.wde authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package xgb
+
+import (
+	"github.com/BurntSushi/xgb/randr"
+	"github.com/BurntSushi/xgbutil"
+	"github.com/BurntSushi/xgbutil/ewmh"
+	"github.com/BurntSushi/xgbutil/xprop"
+	"github.com/skelterjohn/go.wde"
+	"image"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Screens lists the monitors RandR knows of, or the whole root window as a
+// single screen when the server lacks RandR 1.3.
+func Screens() (screens []wde.Screen, err error) {
+	xu, err := xgbutil.NewConn()
+	if err != nil {
+		return
+	}
+	defer xu.Conn().Close()
+	screens = listScreens(xu)
+	return
+}
+
+func listScreens(xu *xgbutil.XUtil) (screens []wde.Screen) {
+	screens = randrScreens(xu)
+	if len(screens) == 0 {
+		root := xu.Screen()
+		s := wde.Screen{
+			Bounds:  image.Rect(0, 0, int(root.WidthInPixels), int(root.HeightInPixels)),
+			Primary: true,
+		}
+		s.DPI = dpi(int(root.WidthInPixels), int(root.WidthInMillimeters))
+		screens = []wde.Screen{s}
+	}
+
+	// _NET_WORKAREA is one rectangle per desktop over the whole root
+	// window, so each screen gets the part of it that covers the screen
+	work := image.Rectangle{}
+	if areas, err := ewmh.WorkareaGet(xu); err == nil && len(areas) != 0 {
+		desk, _ := ewmh.CurrentDesktopGet(xu)
+		if int(desk) >= len(areas) {
+			desk = 0
+		}
+		a := areas[desk]
+		work = image.Rect(a.X, a.Y, a.X+int(a.Width), a.Y+int(a.Height))
+	}
+	xft := xftDPI(xu)
+	for i := range screens {
+		screens[i].Scale = scaleForDPI(xft, screens[i].DPI)
+		screens[i].WorkArea = screens[i].Bounds
+		if !work.Empty() {
+			if r := work.Intersect(screens[i].Bounds); !r.Empty() {
+				screens[i].WorkArea = r
+			}
+		}
+	}
+	return
+}
+
+// randrScreens returns a screen for each CRTC that is showing a connected
+// output, the primary output's first. It returns nil if RandR 1.3 is missing.
+func randrScreens(xu *xgbutil.XUtil) (screens []wde.Screen) {
+	c := xu.Conn()
+	if randr.Init(c) != nil {
+		return
+	}
+	v, err := randr.QueryVersion(c, 1, 3).Reply()
+	if err != nil || v.MajorVersion != 1 || v.MinorVersion < 3 {
+		return
+	}
+	res, err := randr.GetScreenResourcesCurrent(c, xu.RootWin()).Reply()
+	if err != nil {
+		return
+	}
+	var primary randr.Output
+	if p, err := randr.GetOutputPrimary(c, xu.RootWin()).Reply(); err == nil {
+		primary = p.Output
+	}
+	modes := map[uint32]randr.ModeInfo{}
+	for _, m := range res.Modes {
+		modes[m.Id] = m
+	}
+
+	seen := map[randr.Crtc]bool{}
+	for _, output := range res.Outputs {
+		oi, err := randr.GetOutputInfo(c, output, res.ConfigTimestamp).Reply()
+		if err != nil || oi.Connection != randr.ConnectionConnected || oi.Crtc == 0 || seen[oi.Crtc] {
+			continue
+		}
+		ci, err := randr.GetCrtcInfo(c, oi.Crtc, res.ConfigTimestamp).Reply()
+		if err != nil || ci.Width == 0 || ci.Height == 0 {
+			continue
+		}
+		seen[oi.Crtc] = true
+
+		s := wde.Screen{
+			Name:    string(oi.Name),
+			Bounds:  image.Rect(int(ci.X), int(ci.Y), int(ci.X)+int(ci.Width), int(ci.Y)+int(ci.Height)),
+			Primary: output == primary,
+		}
+		mmWidth := int(oi.MmWidth)
+		if ci.Rotation&(randr.RotationRotate90|randr.RotationRotate270) != 0 {
+			mmWidth = int(oi.MmHeight)
+		}
+		s.DPI = dpi(int(ci.Width), mmWidth)
+		if m, ok := modes[uint32(ci.Mode)]; ok && m.Htotal != 0 && m.Vtotal != 0 {
+			lines := float64(m.Vtotal)
+			if m.ModeFlags&randr.ModeFlagDoubleScan != 0 {
+				lines *= 2
+			}
+			if m.ModeFlags&randr.ModeFlagInterlace != 0 {
+				lines /= 2
+			}
+			s.RefreshRate = float64(m.DotClock) / (float64(m.Htotal) * lines)
+		}
+
+		if s.Primary {
+			screens = append([]wde.Screen{s}, screens...)
+		} else {
+			screens = append(screens, s)
+		}
+	}
+	return
+}
+
+// dpi returns the resolution of pixels spread over mm millimetres.
+func dpi(pixels, mm int) float64 {
+	if mm <= 0 {
+		return 0
+	}
+	return float64(pixels) * 25.4 / float64(mm)
+}
+
+/*
+xftDPI returns the Xft.dpi resource, which desktops set to the DPI the user
+wants text drawn at, or 0 if it is not set. It lives in the RESOURCE_MANAGER
+property of the root window, as lines of "name:\tvalue".
+*/
+func xftDPI(xu *xgbutil.XUtil) float64 {
+	reply, err := xprop.GetProperty(xu, xu.RootWin(), "RESOURCE_MANAGER")
+	if err != nil {
+		return 0
+	}
+	for _, line := range strings.Split(string(reply.Value), "\n") {
+		if !strings.HasPrefix(line, "Xft.dpi:") {
+			continue
+		}
+		dpi, err := strconv.ParseFloat(strings.TrimSpace(line[len("Xft.dpi:"):]), 64)
+		if err == nil && dpi > 0 {
+			return dpi
+		}
+	}
+	return 0
+}
+
+/*
+scaleForDPI returns the scale of a screen. Xft.dpi, where set, applies to
+every screen. Otherwise the screen's physical DPI is used, in steps of a
+quarter so that slightly dense screens stay at 1, since monitors do not
+always report their size accurately.
+*/
+func scaleForDPI(xft, dpi float64) float64 {
+	if xft > 0 {
+		return xft / 96
+	}
+	scale := math.Floor(dpi/96*4+0.5) / 4
+	if scale < 1 {
+		scale = 1
+	}
+	return scale
+}
+
+// screenOf returns the screen that holds the middle of r.
+func screenOf(xu *xgbutil.XUtil, r image.Rectangle) (s wde.Screen, ok bool) {
+	return wde.ScreenAt(listScreens(xu), r.Min.Add(r.Size().Div(2)))
+}
+
+// scaleOf returns the scale of the screen of screens that holds the middle
+// of r, or 1 if none does.
+func scaleOf(screens []wde.Screen, r image.Rectangle) float64 {
+	s, ok := wde.ScreenAt(screens, r.Min.Add(r.Size().Div(2)))
+	if !ok {
+		return 1
+	}
+	return s.Scale
+}
@@ -14,16 +14,29 @@
    limitations under the License.
 */
 
+/*
+Package xgb is the go.wde backend for X11, written in pure Go on top of
+xgb and xgbutil.
+
+Text input is limited to what the backend composes itself. It reads the
+keyboard layout and combines the common dead keys with the letter that
+follows them, sending a TextCompositionEvent with the accent while one is
+pending. It does not speak XIM, so input methods such as those for Chinese,
+Japanese and Korean cannot be used, and it does not read Compose files, so
+Multi_key sequences type nothing.
+*/
 package xgb
 
 import (
 	"fmt"
 	"github.com/BurntSushi/xgb"
+	"github.com/BurntSushi/xgb/randr"
 	"github.com/BurntSushi/xgb/xproto"
 	"github.com/BurntSushi/xgbutil"
 	"github.com/BurntSushi/xgbutil/ewmh"
 	"github.com/BurntSushi/xgbutil/icccm"
 	"github.com/BurntSushi/xgbutil/keybind"
+	"github.com/BurntSushi/xgbutil/motif"
 	"github.com/BurntSushi/xgbutil/xgraphics"
 	"github.com/BurntSushi/xgbutil/xwindow"
 	"github.com/skelterjohn/go.wde"
@@ -43,6 +56,11 @@ func init() {
 	wde.BackendStop = func() {
 		ch <- struct{}{}
 	}
+	wde.BackendNewWindowWithOptions = func(opts wde.WindowOptions) (w wde.Window, err error) {
+		w, err = NewWindowWithOptions(opts)
+		return
+	}
+	wde.BackendScreens = Screens
 }
 
 const AllEventsMask = xproto.EventMaskKeyPress |
@@ -52,7 +70,10 @@ const AllEventsMask = xproto.EventMaskKeyPress |
 	xproto.EventMaskEnterWindow |
 	xproto.EventMaskLeaveWindow |
 	xproto.EventMaskPointerMotion |
-	xproto.EventMaskStructureNotify
+	xproto.EventMaskStructureNotify |
+	xproto.EventMaskFocusChange |
+	xproto.EventMaskPropertyChange |
+	xproto.EventMaskExposure
 
 type Window struct {
 	win           *xwindow.Window
@@ -62,15 +83,40 @@ type Window struct {
 	bufferLck     *sync.Mutex
 	width, height int
 	lockedSize    bool
+	shown         bool
 	closed        bool
 
-	events chan interface{}
+	// minSize and maxSize are the limits WindowOptions gave, and placed is
+	// set if it gave a position
+	minSize, maxSize image.Point
+	placed           bool
+	// origin is where the window was created
+	origin image.Point
+
+	// scale is only changed by the event loop; scaleLck lets Scale read it
+	scaleLck sync.Mutex
+	scale    float64
+
+	events chan wde.Event
 }
 
 func NewWindow(width, height int) (w *Window, err error) {
+	return NewWindowWithOptions(wde.WindowOptions{Width: width, Height: height})
+}
+
+/*
+NewWindowWithOptions creates a window as opts describe. The options become
+window attributes and ICCCM, EWMH and Motif properties, all set before the
+window is mapped, so the window manager takes the window in as it should
+be.
+*/
+func NewWindowWithOptions(opts wde.WindowOptions) (w *Window, err error) {
+	width, height := opts.Width, opts.Height
 
 	w = new(Window)
 	w.width, w.height = width, height
+	w.lockedSize = opts.FixedSize
+	w.minSize, w.maxSize = opts.MinSize, opts.MaxSize
 
 	w.xu, err = xgbutil.NewConn()
 	if err != nil {
@@ -85,28 +131,41 @@ func NewWindow(width, height int) (w *Window, err error) {
 		return
 	}
 
-	err = w.win.CreateChecked(screen.Root, 600, 500, width, height, 0)
+	// the window manager decides where the window goes, unless told
+	x, y := 0, 0
+	if opts.Position != nil {
+		x, y = opts.Position.X, opts.Position.Y
+		w.placed = true
+	}
+	w.origin = image.Pt(x, y)
+	err = w.win.CreateChecked(screen.Root, x, y, width, height,
+		xproto.CwEventMask, AllEventsMask)
 	if err != nil {
 		return
 	}
 
-	w.win.Listen(AllEventsMask)
-
 	err = icccm.WmProtocolsSet(w.xu, w.win.Id, []string{"WM_DELETE_WINDOW"})
 	if err != nil {
 		fmt.Println(err)
 		err = nil
 	}
+	w.applyOptions(opts)
 
 	w.bufferLck = &sync.Mutex{}
 	w.buffer = xgraphics.New(w.xu, image.Rect(0, 0, width, height))
 	w.buffer.XSurfaceSet(w.win.Id)
 
-	keyMap, modMap := keybind.MapsGet(w.xu)
-	keybind.KeyMapSet(w.xu, keyMap)
-	keybind.ModMapSet(w.xu, modMap)
+	w.scale = scaleOf(listScreens(w.xu), image.Rect(x, y, x+width, y+height))
+	// hear of monitors coming, going and changing, to keep the scale right
+	if randr.Init(w.conn) == nil {
+		randr.SelectInput(w.conn, screen.Root, randr.NotifyMaskScreenChange)
+	}
 
-	w.events = make(chan interface{})
+	w.loadKeymap()
+	// without it, held keys still repeat, but Repeat is never set
+	enableDetectableAutoRepeat(w.conn)
+
+	w.events = make(chan wde.Event)
 
 	w.SetIcon(Gordon)
 	w.SetIconName("Go")
@@ -116,6 +175,48 @@ func NewWindow(width, height int) (w *Window, err error) {
 	return
 }
 
+// applyOptions sets the properties the window manager reads as it maps the
+// window.
+func (w *Window) applyOptions(opts wde.WindowOptions) {
+	if opts.Title != "" {
+		ewmh.WmNameSet(w.xu, w.win.Id, opts.Title)
+	}
+	w.updateSizeHints()
+	if opts.Undecorated {
+		motif.WmHintsSet(w.xu, w.win.Id, &motif.Hints{
+			Flags:      motif.HintDecorations,
+			Decoration: motif.DecorationNone,
+		})
+	}
+	if parent, ok := opts.Parent.(*Window); ok {
+		icccm.WmTransientForSet(w.xu, w.win.Id, parent.win.Id)
+	}
+
+	var states []string
+	if opts.AlwaysOnTop {
+		states = append(states, "_NET_WM_STATE_ABOVE")
+	}
+	switch opts.State {
+	case wde.StateMaximized:
+		states = append(states, "_NET_WM_STATE_MAXIMIZED_VERT", "_NET_WM_STATE_MAXIMIZED_HORZ")
+	case wde.StateFullscreen:
+		states = append(states, "_NET_WM_STATE_FULLSCREEN")
+	case wde.StateMinimized:
+		w.setInitialState(icccm.StateIconic)
+	}
+	if len(states) != 0 {
+		ewmh.WmStateSet(w.xu, w.win.Id, states)
+	}
+}
+
+// loadKeymap fetches the keyboard and modifier mappings that keys are
+// translated with.
+func (w *Window) loadKeymap() {
+	keyMap, modMap := keybind.MapsGet(w.xu)
+	keybind.KeyMapSet(w.xu, keyMap)
+	keybind.ModMapSet(w.xu, modMap)
+}
+
 func (w *Window) SetTitle(title string) {
 	if w.closed {
 		return
@@ -148,6 +249,76 @@ func (w *Window) Size() (width, height int) {
 	return
 }
 
+/*
+Position reports where the window's frame is on the root window. With a
+reparenting window manager the frame is an ancestor of w.win, so the
+topmost window below the root is asked.
+*/
+func (w *Window) Position() (x, y int) {
+	if w.closed {
+		return
+	}
+	r, err := w.win.DecorGeometry()
+	if err != nil {
+		return
+	}
+	return r.X(), r.Y()
+}
+
+// SetPosition asks for the window to be moved. Under the default north-west
+// gravity, window managers put the frame's corner at x, y.
+func (w *Window) SetPosition(x, y int) {
+	if w.closed {
+		return
+	}
+	w.win.Move(x, y)
+}
+
+// Center moves the window to the middle of the work area of the screen that
+// holds its middle.
+func (w *Window) Center() {
+	if w.closed {
+		return
+	}
+	g, err := w.win.DecorGeometry()
+	if err != nil {
+		return
+	}
+	r := image.Rect(g.X(), g.Y(), g.X()+g.Width(), g.Y()+g.Height())
+	s, ok := screenOf(w.xu, r)
+	if !ok {
+		return
+	}
+	p := s.WorkArea.Min.Add(s.WorkArea.Size().Sub(r.Size()).Div(2))
+	w.win.Move(p.X, p.Y)
+}
+
+func (w *Window) Scale() float64 {
+	w.scaleLck.Lock()
+	defer w.scaleLck.Unlock()
+	return w.scale
+}
+
+// rootPosition returns where the window's top-left corner is on the root
+// window.
+func (w *Window) rootPosition() (p image.Point, ok bool) {
+	reply, err := xproto.TranslateCoordinates(w.conn, w.win.Id, w.xu.RootWin(), 0, 0).Reply()
+	if err != nil {
+		return
+	}
+	return image.Pt(int(reply.DstX), int(reply.DstY)), true
+}
+
+// frameInset returns where the window is within the frame the window manager
+// put around it, from _NET_FRAME_EXTENTS.
+func (w *Window) frameInset() image.Point {
+	extents, err := ewmh.FrameExtentsGet(w.xu, w.win.Id)
+	if err != nil {
+		return image.ZP
+	}
+	return image.Pt(extents.Left, extents.Top)
+}
+
 func (w *Window) LockSize(lock bool) {
 	w.lockedSize = lock
 	w.updateSizeHints()
@@ -155,12 +326,33 @@ func (w *Window) LockSize(lock bool) {
 
 func (w *Window) updateSizeHints() {
 	hints := new(icccm.NormalHints)
+	if w.placed {
+		// the position itself is taken from the window
+		hints.Flags |= icccm.SizeHintUSPosition
+	}
 	if w.lockedSize {
-		hints.Flags = icccm.SizeHintPMinSize | icccm.SizeHintPMaxSize
+		hints.Flags |= icccm.SizeHintPMinSize | icccm.SizeHintPMaxSize
 		hints.MinWidth = uint(w.width)
 		hints.MaxWidth = uint(w.width)
 		hints.MinHeight = uint(w.height)
 		hints.MaxHeight = uint(w.height)
+	} else {
+		if w.minSize != image.ZP {
+			hints.Flags |= icccm.SizeHintPMinSize
+			hints.MinWidth = uint(w.minSize.X)
+			hints.MinHeight = uint(w.minSize.Y)
+		}
+		if w.maxSize != image.ZP {
+			// a zero dimension has no limit, but the hint needs one
+			hints.Flags |= icccm.SizeHintPMaxSize
+			hints.MaxWidth, hints.MaxHeight = 1<<15-1, 1<<15-1
+			if w.maxSize.X > 0 {
+				hints.MaxWidth = uint(w.maxSize.X)
+			}
+			if w.maxSize.Y > 0 {
+				hints.MaxHeight = uint(w.maxSize.Y)
+			}
+		}
 	}
 	icccm.WmNormalHintsSet(w.xu, w.win.Id, hints)
 }
@@ -169,9 +361,125 @@ func (w *Window) Show() {
 	if w.closed {
 		return
 	}
+	w.shown = true
 	w.win.Map()
 }
 
+func (w *Window) SetFullscreen(fullscreen bool) {
+	if w.closed {
+		return
+	}
+	w.setWmState(fullscreen, "_NET_WM_STATE_FULLSCREEN")
+}
+
+func (w *Window) Maximize() {
+	if w.closed {
+		return
+	}
+	w.setWmState(true, "_NET_WM_STATE_MAXIMIZED_VERT", "_NET_WM_STATE_MAXIMIZED_HORZ")
+}
+
+// Minimize asks the window manager to iconify the window. Before Show, it
+// has Show map the window iconified.
+func (w *Window) Minimize() {
+	if w.closed {
+		return
+	}
+	if !w.shown {
+		w.setInitialState(icccm.StateIconic)
+		return
+	}
+	ewmh.ClientEvent(w.xu, w.win.Id, "WM_CHANGE_STATE", icccm.StateIconic)
+}
+
+func (w *Window) Restore() {
+	if w.closed {
+		return
+	}
+	w.setWmState(false, "_NET_WM_STATE_FULLSCREEN")
+	w.setWmState(false, "_NET_WM_STATE_MAXIMIZED_VERT", "_NET_WM_STATE_MAXIMIZED_HORZ")
+	if !w.shown {
+		w.setInitialState(icccm.StateNormal)
+	} else if w.state() == wde.StateMinimized {
+		// mapping an iconic window is how ICCCM asks for it back
+		w.win.Map()
+	}
+}
+
+// setInitialState sets the state WM_HINTS asks the window manager to map
+// the window in, keeping the other hints.
+func (w *Window) setInitialState(state uint) {
+	hints, err := icccm.WmHintsGet(w.xu, w.win.Id)
+	if err != nil {
+		hints = new(icccm.Hints)
+	}
+	hints.Flags |= icccm.HintState
+	hints.InitialState = state
+	icccm.WmHintsSet(w.xu, w.win.Id, hints)
+}
+
+/*
+setWmState adds or removes one or two of the _NET_WM_STATE atoms. Once the
+window is shown, the window manager is asked to change them; before then,
+EWMH has the property set directly, for the window manager to read when the
+window is mapped.
+*/
+func (w *Window) setWmState(add bool, states ...string) {
+	if !w.shown {
+		current, _ := ewmh.WmStateGet(w.xu, w.win.Id)
+		keep := []string{}
+		for _, c := range current {
+			if c != states[0] && c != states[len(states)-1] {
+				keep = append(keep, c)
+			}
+		}
+		if add {
+			keep = append(keep, states...)
+		}
+		ewmh.WmStateSet(w.xu, w.win.Id, keep)
+		return
+	}
+	action := ewmh.StateRemove
+	if add {
+		action = ewmh.StateAdd
+	}
+	if len(states) == 2 {
+		ewmh.WmStateReqExtra(w.xu, w.win.Id, action, states[0], states[1], 1)
+	} else {
+		ewmh.WmStateReq(w.xu, w.win.Id, action, states[0])
+	}
+}
+
+/*
+state works out the window's state from the properties the window manager
+keeps on it. A window is only taken to be maximized when it is maximized
+both ways.
+*/
+func (w *Window) state() wde.WindowState {
+	if s, err := icccm.WmStateGet(w.xu, w.win.Id); err == nil && s.State == icccm.StateIconic {
+		return wde.StateMinimized
+	}
+	states, _ := ewmh.WmStateGet(w.xu, w.win.Id)
+	fullscreen, maxed := false, 0
+	for _, s := range states {
+		switch s {
+		case "_NET_WM_STATE_HIDDEN":
+			return wde.StateMinimized
+		case "_NET_WM_STATE_FULLSCREEN":
+			fullscreen = true
+		case "_NET_WM_STATE_MAXIMIZED_VERT", "_NET_WM_STATE_MAXIMIZED_HORZ":
+			maxed++
+		}
+	}
+	switch {
+	case fullscreen:
+		return wde.StateFullscreen
+	case maxed == 2:
+		return wde.StateMaximized
+	}
+	return wde.StateNormal
+}
+
 func (w *Window) Screen() (im wde.Image) {
 	if w.closed {
 		return
@@ -192,8 +500,37 @@ func (w *Window) FlushImage(bounds ...image.Rectangle) {
 		}
 		w.bufferLck.Unlock()
 	}
-	w.buffer.XDraw()
-	w.buffer.XPaint(w.win.Id)
+	rects := wde.FlushRects(w.buffer.Bounds(), bounds)
+	if len(rects) == 1 && rects[0] == w.buffer.Bounds() {
+		w.buffer.XDraw()
+		w.buffer.XPaint(w.win.Id)
+		return
+	}
+	// send only what changed to the pixmap, and then only that on to the
+	// window
+	for _, r := range rects {
+		w.buffer.SubImage(r).(*xgraphics.Image).XDraw()
+	}
+	w.paintRects(rects)
+}
+
+// paintRects clears rects of the window, which the server fills in from its
+// background: the pixmap that XDraw left the last flushed frame in.
+func (w *Window) paintRects(rects []image.Rectangle) {
+	w.bufferLck.Lock()
+	defer w.bufferLck.Unlock()
+	if w.buffer.Pixmap == 0 {
+		return
+	}
+	for _, r := range rects {
+		r = r.Intersect(w.buffer.Bounds())
+		if r.Empty() {
+			continue
+		}
+		xproto.ClearArea(w.conn, false, w.win.Id,
+			int16(r.Min.X), int16(r.Min.Y),
+			uint16(r.Dx()), uint16(r.Dy()))
+	}
 }
 
 func (w *Window) Close() (err error) {
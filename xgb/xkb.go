@@ -0,0 +1,75 @@
+/*
+   Copyright 2012 the go.wde authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package xgb
+
+import (
+	"github.com/BurntSushi/xgb"
+	"github.com/BurntSushi/xgb/xproto"
+)
+
+// xgb has no binding for the XKEYBOARD extension, so the two requests
+// needed here are built by hand.
+const (
+	xkbUseExtension   = 0
+	xkbPerClientFlags = 21
+
+	xkbUseCoreKbd               = 0x100
+	xkbDetectableAutoRepeatFlag = 1
+)
+
+/*
+enableDetectableAutoRepeat asks the server to send a held key's repeats as
+key presses alone, instead of as release and press pairs that cannot be
+told apart from the user tapping the key. It reports whether the server
+agreed.
+*/
+func enableDetectableAutoRepeat(c *xgb.Conn) bool {
+	const name = "XKEYBOARD"
+	ext, err := xproto.QueryExtension(c, uint16(len(name)), name).Reply()
+	if err != nil || !ext.Present {
+		return false
+	}
+
+	buf := make([]byte, 8)
+	buf[0] = ext.MajorOpcode
+	buf[1] = xkbUseExtension
+	xgb.Put16(buf[2:], uint16(len(buf)/4))
+	xgb.Put16(buf[4:], 1) // wantedMajor
+	xgb.Put16(buf[6:], 0) // wantedMinor
+	cookie := c.NewCookie(true, true)
+	c.NewRequest(buf, cookie)
+	reply, err := cookie.Reply()
+	if err != nil || reply == nil || reply[1] == 0 {
+		return false
+	}
+
+	buf = make([]byte, 28)
+	buf[0] = ext.MajorOpcode
+	buf[1] = xkbPerClientFlags
+	xgb.Put16(buf[2:], uint16(len(buf)/4))
+	xgb.Put16(buf[4:], xkbUseCoreKbd)
+	xgb.Put32(buf[8:], xkbDetectableAutoRepeatFlag)  // change
+	xgb.Put32(buf[12:], xkbDetectableAutoRepeatFlag) // value
+	cookie = c.NewCookie(true, true)
+	c.NewRequest(buf, cookie)
+	reply, err = cookie.Reply()
+	if err != nil || len(reply) < 16 {
+		return false
+	}
+	// the reply's value field holds the flags now in effect
+	return xgb.Get32(reply[12:])&xkbDetectableAutoRepeatFlag != 0
+}